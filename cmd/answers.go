@@ -0,0 +1,236 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Used to set values from the command line without going through the menus
+type fieldFlag struct {
+	name  string
+	usage string
+	// Returns a pointer to the field of the Template the flag sets
+	field func(t *Template) interface{}
+	// Optional, used to enable the parent section when the flag is set
+	enable func(t *Template)
+}
+
+// Holds the values parsed from the command line flags
+var flagTemplate = defaultTemplate()
+
+// Every field which can be set from the command line with 'create values'
+var fieldFlags = []fieldFlag{
+	{name: "cluster-domain", usage: "wildcard domain used for the cnvrg.io install",
+		field: func(t *Template) interface{} { return &t.ClusterDomain.ClusterDomain }},
+	{name: "internal-domain", usage: "internal cluster domain",
+		field: func(t *Template) interface{} { return &t.ClusterInteralDomain.Domain }},
+	{name: "image-hub", usage: "image hub to pull the cnvrg.io images from",
+		field: func(t *Template) interface{} { return &t.ClusterDomain.ImageHub }},
+	{name: "labels", usage: "labels added to every resource, key=value",
+		field: func(t *Template) interface{} { return (*map[string]string)(&t.Labels) }},
+	{name: "annotations", usage: "annotations added to every resource, key=value",
+		field: func(t *Template) interface{} { return (*map[string]string)(&t.Annotations) }},
+	{name: "https", usage: "enable HTTPS",
+		field: func(t *Template) interface{} { return &t.Network.Https.Enabled }},
+	{name: "cert-secret", usage: "name of the certificate secret used for HTTPS",
+		field:  func(t *Template) interface{} { return &t.Network.Https.CertSecret },
+		enable: func(t *Template) { t.Network.Https.Enabled = true }},
+	{name: "ingress-type", usage: "ingress type [istio|ingress|openshift|nodeport]",
+		field: func(t *Template) interface{} { return &t.Network.Ingress.Type },
+		enable: func(t *Template) {
+			if t.Network.Ingress.Type != "istio" {
+				t.Network.Istio.Enabled = false
+			}
+		}},
+	{name: "http-proxy", usage: "HTTP proxy addresses, comma separated",
+		field:  func(t *Template) interface{} { return &t.Network.Proxy.HttpProxy },
+		enable: func(t *Template) { t.Network.Proxy.Enabled = true }},
+	{name: "https-proxy", usage: "HTTPS proxy addresses, comma separated",
+		field:  func(t *Template) interface{} { return &t.Network.Proxy.HttpsProxy },
+		enable: func(t *Template) { t.Network.Proxy.Enabled = true }},
+	{name: "no-proxy", usage: "addresses which bypass the proxy, comma separated",
+		field:  func(t *Template) interface{} { return &t.Network.Proxy.NoProxy },
+		enable: func(t *Template) { t.Network.Proxy.Enabled = true }},
+	{name: "registry-url", usage: "URL of the registry to pull images from",
+		field:  func(t *Template) interface{} { return &t.Registry.Url },
+		enable: func(t *Template) { t.Registry.Enabled = true }},
+	{name: "registry-user", usage: "user name of the registry",
+		field:  func(t *Template) interface{} { return &t.Registry.User },
+		enable: func(t *Template) { t.Registry.Enabled = true }},
+	{name: "registry-password", usage: "password of the registry",
+		field:  func(t *Template) interface{} { return &t.Registry.Password },
+		enable: func(t *Template) { t.Registry.Enabled = true }},
+	{name: "tenancy-key", usage: "tenancy node selector key",
+		field:  func(t *Template) interface{} { return &t.Tenancy.Key },
+		enable: func(t *Template) { t.Tenancy.Enabled = true }},
+	{name: "tenancy-value", usage: "tenancy node selector value",
+		field:  func(t *Template) interface{} { return &t.Tenancy.Value },
+		enable: func(t *Template) { t.Tenancy.Enabled = true }},
+	{name: "control-plane-image", usage: "image of the control plane, like cnvrg/app:v4.7.33",
+		field: func(t *Template) interface{} { return &t.ControlPlane.Image }},
+	{name: "agent-tag", usage: "custom tag of the cnvrg.io agent images",
+		field: func(t *Template) interface{} { return &t.ControlPlane.BaseConfigAgentTag }},
+	{name: "intercom", usage: "enable intercom",
+		field: func(t *Template) interface{} { return &t.ControlPlane.BaseConfigIntercom }},
+	{name: "privileged-jobs", usage: "run cnvrg.io jobs as privileged",
+		field: func(t *Template) interface{} { return &t.ControlPlane.BaseConfigCnvrgPrivileged }},
+	{name: "feature-flags", usage: "feature flags of the control plane, flag=value",
+		field: func(t *Template) interface{} { return &t.ControlPlane.BaseConfigFeatureFlags }},
+	{name: "smtp-server", usage: "SMTP server used to send email",
+		field: func(t *Template) interface{} { return &t.ControlPlane.SmtpServer }},
+	{name: "smtp-port", usage: "port of the SMTP server",
+		field: func(t *Template) interface{} { return &t.ControlPlane.SmtpPort }},
+	{name: "smtp-username", usage: "user name of the SMTP server",
+		field: func(t *Template) interface{} { return &t.ControlPlane.SmtpUsername }},
+	{name: "smtp-password", usage: "password of the SMTP server",
+		field: func(t *Template) interface{} { return &t.ControlPlane.SmtpPassword }},
+	{name: "smtp-sender", usage: "email address the email is sent from",
+		field: func(t *Template) interface{} { return &t.ControlPlane.SmtpSender }},
+	{name: "object-storage-type", usage: "object storage type [minio|aws|azure|gcp]",
+		field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageType }},
+	{name: "object-storage-bucket", usage: "object storage bucket",
+		field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageBucket }},
+	{name: "object-storage-region", usage: "object storage region",
+		field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageRegion }},
+	{name: "object-storage-access-key", usage: "object storage access key",
+		field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageAccessKey }},
+	{name: "object-storage-secret-key", usage: "object storage secret key",
+		field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageSecretKey }},
+	{name: "object-storage-endpoint", usage: "URL of an S3 compatible object storage endpoint",
+		field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageEndpoint }},
+	{name: "webapp-replicas", usage: "replicas of the webapp",
+		field: func(t *Template) interface{} { return &t.ControlPlane.WebappReplicas }},
+	{name: "webapp-hpa", usage: "enable the HPA of the webapp",
+		field: func(t *Template) interface{} { return &t.ControlPlane.WebappHpaEnable }},
+	{name: "webapp-hpa-max-replicas", usage: "max replicas of the webapp HPA",
+		field: func(t *Template) interface{} { return &t.ControlPlane.WebappHpaMaxReplicas }},
+	{name: "sidekiq-hpa", usage: "enable the HPA of sidekiq",
+		field: func(t *Template) interface{} { return &t.ControlPlane.SidekiqHpaEnable }},
+	{name: "sidekiq-hpa-max-replicas", usage: "max replicas of the sidekiq HPA",
+		field: func(t *Template) interface{} { return &t.ControlPlane.SidekiqHpaMaxReplicas }},
+	{name: "sidekiq-split", usage: "split sidekiq into separate deployments",
+		field: func(t *Template) interface{} { return &t.ControlPlane.SidekiqSplit }},
+	{name: "searchkiq-hpa", usage: "enable the HPA of searchkiq",
+		field: func(t *Template) interface{} { return &t.ControlPlane.SearchkiqHpaEnable }},
+	{name: "searchkiq-hpa-max-replicas", usage: "max replicas of the searchkiq HPA",
+		field: func(t *Template) interface{} { return &t.ControlPlane.SearchkiqHpaMaxReplicas }},
+	{name: "systemkiq-hpa", usage: "enable the HPA of systemkiq",
+		field: func(t *Template) interface{} { return &t.ControlPlane.SystemkiqHpaEnable }},
+	{name: "systemkiq-hpa-max-replicas", usage: "max replicas of the systemkiq HPA",
+		field: func(t *Template) interface{} { return &t.ControlPlane.SystemkiqHpaMaxReplicas }},
+	{name: "mpi-image", usage: "image of the MPI operator",
+		field: func(t *Template) interface{} { return &t.ControlPlane.MpiImage }},
+	{name: "mpi-kubectl-image", usage: "kubectl delivery image of the MPI operator",
+		field: func(t *Template) interface{} { return &t.ControlPlane.MpiKubectlImage }},
+	{name: "mpi-extra-args", usage: "extra args of the MPI operator, --arg=value",
+		field: func(t *Template) interface{} { return &t.ControlPlane.MpiExtraArgs }},
+	{name: "mpi-registry-url", usage: "URL of the registry to pull the MPI images from",
+		field: func(t *Template) interface{} { return &t.ControlPlane.MpiRegistryUrl }},
+	{name: "mpi-registry-user", usage: "user name of the MPI registry",
+		field: func(t *Template) interface{} { return &t.ControlPlane.MpiRegistryUser }},
+	{name: "mpi-registry-password", usage: "password of the MPI registry",
+		field: func(t *Template) interface{} { return &t.ControlPlane.MpiRegistryPassword }},
+	{name: "nfs-server", usage: "NFS server IP address",
+		field:  func(t *Template) interface{} { return &t.Storage.Nfs.Server },
+		enable: func(t *Template) { t.Storage.Nfs.Enabled = true }},
+	{name: "nfs-path", usage: "NFS export path",
+		field:  func(t *Template) interface{} { return &t.Storage.Nfs.Path },
+		enable: func(t *Template) { t.Storage.Nfs.Enabled = true }},
+}
+
+// Registers every field flag on the command
+func addFieldFlags(cmd *cobra.Command) {
+	for _, f := range fieldFlags {
+		switch p := f.field(&flagTemplate).(type) {
+		case *string:
+			cmd.Flags().StringVar(p, f.name, *p, f.usage)
+		case *bool:
+			cmd.Flags().BoolVar(p, f.name, *p, f.usage)
+		case *int:
+			cmd.Flags().IntVar(p, f.name, *p, f.usage)
+		case *[]string:
+			cmd.Flags().StringSliceVar(p, f.name, *p, f.usage)
+		case *map[string]string:
+			cmd.Flags().StringToStringVar(p, f.name, *p, f.usage)
+		}
+	}
+}
+
+// Returns true if any of the field flags were set on the command line
+func fieldFlagsChanged(cmd *cobra.Command) bool {
+	for _, f := range fieldFlags {
+		if cmd.Flags().Changed(f.name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Copies the field flags which were set on the command line into the Template
+func applyFieldFlags(cmd *cobra.Command, t *Template) {
+	for _, f := range fieldFlags {
+		if !cmd.Flags().Changed(f.name) {
+			continue
+		}
+		switch dst := f.field(t).(type) {
+		case *string:
+			*dst = *f.field(&flagTemplate).(*string)
+		case *bool:
+			*dst = *f.field(&flagTemplate).(*bool)
+		case *int:
+			*dst = *f.field(&flagTemplate).(*int)
+		case *[]string:
+			*dst = *f.field(&flagTemplate).(*[]string)
+		case *map[string]string:
+			*dst = *f.field(&flagTemplate).(*map[string]string)
+		}
+		if f.enable != nil {
+			f.enable(t)
+		}
+		InfoLogger.Printf("Set %v from the command line\n", f.name)
+	}
+}
+
+// Reads an answers file and sets its values on top of the Template.
+// The answers file uses the same layout as the Template struct and
+// any field not in the file keeps its current value.
+func loadAnswers(name string, t *Template) error {
+	InfoLogger.Printf("Loading answers from %v\n", name)
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(t); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("unable to parse answers file %v: %w", name, err)
+	}
+	return nil
+}
+
+// Validates and renders the Template to the values file without any prompts
+func generateValues(t Template) error {
+	InfoLogger.Println("Generating the values file without prompts")
+	if errs := validateTemplate(t); len(errs) > 0 {
+		printValidationErrors(errs)
+		return fmt.Errorf("the values are not valid, %v was not generated", valuesFile)
+	}
+	if err := checkSmtpServer(t.ControlPlane); err != nil {
+		return err
+	}
+	if err := createFile(valuesFile, &t); err != nil {
+		return err
+	}
+	fmt.Printf("%v Generated the %v file\n", colorGreen, valuesFile)
+	return createSecretsFile(secretsFile, &t)
+}
@@ -13,7 +13,7 @@ import (
 // imagesCmd represents the images command
 var imagesCmd = &cobra.Command{
 	Use:   "images",
-	Short: "A brief description of your command",
+	Short: "Manage the container images used by cnvrg.io",
 	Long: `A longer description that spans multiple lines and likely contains examples
 and usage of using your command. For example:
 
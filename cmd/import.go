@@ -0,0 +1,146 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Set by the flags of the import command
+var importRegistry string
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <bundle.tar>",
+	Short: "Import the images from an air-gapped bundle",
+	Long: `Import every image from a bundle created with 'images save'. The
+images are pushed to the registry when one is given, otherwise they are
+loaded into the local docker daemon, for example:
+
+  cnvrg-deploy-cli images import bundle.tar --registry registry.example.com
+
+The images are copied with skopeo, which must be installed.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		InfoLogger.Println("In the import command")
+		return importBundle(args[0], importRegistry)
+	},
+}
+
+func init() {
+	imagesCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVar(&importRegistry, "registry", "", "registry to push the images to instead of the local docker daemon")
+	importCmd.Flags().StringVar(&skopeoBinary, "skopeo", "skopeo", "skopeo binary used to copy the images")
+}
+
+// The parts of the OCI image layout index.json used to find the images
+type ociIndex struct {
+	Manifests []struct {
+		Annotations map[string]string `json:"annotations"`
+	} `json:"manifests"`
+}
+
+// Extracts the bundle and copies every image in it to the registry
+// or the local docker daemon
+func importBundle(name string, registry string) error {
+	dir, err := os.MkdirTemp("", "cnvrg-bundle")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := extractTar(name, dir); err != nil {
+		ErrorLogger.Println(err)
+		return fmt.Errorf("unable to extract bundle %v: %w", name, err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		return fmt.Errorf("%v is not an image bundle: %w", name, err)
+	}
+	var index ociIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return fmt.Errorf("unable to parse the index of %v: %w", name, err)
+	}
+
+	var failed []string
+	for i, m := range index.Manifests {
+		image := m.Annotations["org.opencontainers.image.ref.name"]
+		if image == "" {
+			continue
+		}
+		target := "docker-daemon:" + image
+		if registry != "" {
+			target = "docker://" + retagImage(image, registry)
+		}
+		fmt.Printf("%v [%d/%d] Importing %v\n", colorBlue, i+1, len(index.Manifests), image)
+		if err := runCommand(skopeoBinary, "copy", "oci:"+dir+":"+image, target); err != nil {
+			ErrorLogger.Printf("Failed to import %v: %v\n", image, err)
+			fmt.Printf("%v   FAILED: %v\n", colorYellow, err)
+			failed = append(failed, image)
+			continue
+		}
+		fmt.Printf("%v   OK\n", colorGreen)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d images failed to import", len(failed), len(index.Manifests))
+	}
+	fmt.Printf("%v Imported %d images from %v\n", colorGreen, len(index.Manifests), name)
+	return nil
+}
+
+// Extracts a tar archive into the directory
+func extractTar(name string, dir string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	tr := tar.NewReader(file)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid file path %v in archive", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
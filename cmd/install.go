@@ -0,0 +1,131 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Name and URL of the cnvrg.io Helm repo
+const (
+	helmRepoName = "cnvrgv3"
+	helmRepoUrl  = "https://charts.v3.cnvrg.io"
+	helmChart    = helmRepoName + "/cnvrg"
+)
+
+// Set by the flags of the install command
+var (
+	installValues    string
+	installSecrets   string
+	installNamespace string
+	installVersion   string
+	installRelease   string
+	installTimeout   string
+	helmBinary       string
+)
+
+// installCmd represents the install command
+var installCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install cnvrg.io with Helm using a values file",
+	Long: `Add the cnvrg.io Helm repo and install or upgrade the cnvrg release
+with the values file. The values file is validated before Helm runs and
+the output of Helm is streamed as it runs, for example:
+
+  cnvrg-deploy-cli install --values values.yaml --namespace cnvrg --version 4.7.33
+
+When the passwords were written to a separate file with
+'create values --secrets file', pass it with --secrets-file.
+
+The helm binary must be installed.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		InfoLogger.Println("In the install command")
+		t, err := readValuesFile(installValuesFiles()...)
+		if err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
+		if errs := validateTemplate(t); len(errs) > 0 {
+			printValidationErrors(errs)
+			return fmt.Errorf("%v is not valid, nothing was installed", installValues)
+		}
+		if err := addHelmRepo(); err != nil {
+			return err
+		}
+		return runHelmRelease("install", installValuesFiles()...)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(installCmd)
+
+	installCmd.Flags().StringVar(&installValues, "values", "values.yaml", "values file to install cnvrg.io with")
+	installCmd.Flags().StringVar(&installSecrets, "secrets-file", "", "secrets file written by 'create values --secrets file', merged into the values")
+	installCmd.Flags().StringVarP(&installNamespace, "namespace", "n", "cnvrg", "namespace to install cnvrg.io in")
+	installCmd.Flags().StringVar(&installVersion, "version", "", "version of the cnvrg chart, the latest when empty")
+	installCmd.Flags().StringVar(&installRelease, "release", "cnvrg", "name of the Helm release")
+	installCmd.Flags().StringVar(&installTimeout, "timeout", "1500s", "time to wait for the install to finish")
+	installCmd.Flags().StringVar(&helmBinary, "helm", "helm", "helm binary used to install cnvrg.io")
+}
+
+// Adds and updates the cnvrg.io Helm repo
+func addHelmRepo() error {
+	fmt.Println((colorGreen), "---------Adding the cnvrg.io Helm repo---------")
+	if err := runHelm("repo", "add", helmRepoName, helmRepoUrl, "--force-update"); err != nil {
+		return err
+	}
+	return runHelm("repo", "update")
+}
+
+// Returns the values files passed to Helm, the secrets file goes last so its values are merged in
+func installValuesFiles() []string {
+	files := []string{installValues}
+	if installSecrets != "" {
+		files = append(files, installSecrets)
+	}
+	return files
+}
+
+// Installs or upgrades the release, an install also upgrades the release
+// when it is already installed
+func runHelmRelease(action string, values ...string) error {
+	fmt.Printf("%v ---------Running the cnvrg.io %v---------\n", colorGreen, action)
+	args := []string{"upgrade", installRelease, helmChart, "-n", installNamespace,
+		"--timeout", installTimeout, "--wait"}
+	for _, v := range values {
+		args = append(args, "--values", v)
+	}
+	if action == "install" {
+		args = append(args, "--install", "--create-namespace")
+	}
+	if installVersion != "" {
+		args = append(args, "--version", installVersion)
+	}
+	if err := runHelm(args...); err != nil {
+		return err
+	}
+	fmt.Printf("%v The cnvrg.io %v in the %v namespace finished\n", colorGreen, action, installNamespace)
+	return nil
+}
+
+// Runs the helm binary, streaming its output to the console
+func runHelm(args ...string) error {
+	InfoLogger.Printf("Running %v %v\n", helmBinary, strings.Join(args, " "))
+	fmt.Printf("%v %v %v\n", colorWhite, helmBinary, strings.Join(args, " "))
+	c := exec.Command(helmBinary, args...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		ErrorLogger.Printf("helm %v failed: %v\n", args[0], err)
+		return fmt.Errorf("helm %v failed: %w", args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// A helm script which logs its arguments, one call per line, and fails the
+// command given in FAKE_HELM_FAIL. The output of list is taken from FAKE_HELM_LIST.
+const fakeHelm = `#!/bin/sh
+echo "$@" >> "$FAKE_HELM_LOG"
+if [ "$1" = "list" ]; then
+	printf '%s' "$FAKE_HELM_LIST"
+fi
+if [ "$1" = "$FAKE_HELM_FAIL" ]; then
+	echo "Error: $1 failed" >&2
+	exit 1
+fi
+`
+
+// Puts the fake helm first on the PATH and returns the file its calls are logged to
+func setupFakeHelm(t *testing.T) string {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "helm"), []byte(fakeHelm), 0755); err != nil {
+		t.Fatal(err)
+	}
+	log := filepath.Join(dir, "calls.log")
+	t.Setenv("FAKE_HELM_LOG", log)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	binary := helmBinary
+	t.Cleanup(func() { helmBinary = binary })
+	helmBinary = "helm"
+	return log
+}
+
+// Returns the arguments of every call made to the fake helm
+func helmCalls(t *testing.T, log string) [][]string {
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls [][]string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		calls = append(calls, strings.Fields(line))
+	}
+	return calls
+}
+
+// Writes a valid values file and sets the flags of the install command
+func setupInstall(t *testing.T) {
+	values := filepath.Join(t.TempDir(), "values.yaml")
+	if err := os.WriteFile(values, []byte("clusterDomain: example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := []string{installValues, installSecrets, installNamespace, installVersion, installRelease, installTimeout}
+	t.Cleanup(func() {
+		installValues, installSecrets, installNamespace, installVersion, installRelease, installTimeout = old[0], old[1], old[2], old[3], old[4], old[5]
+	})
+	installValues = values
+	installSecrets = ""
+	installNamespace = "cnvrg-test"
+	installVersion = "4.7.33"
+	installRelease = "cnvrg"
+	installTimeout = "600s"
+}
+
+func TestInstallRunsHelm(t *testing.T) {
+	log := setupFakeHelm(t)
+	setupInstall(t)
+
+	if err := installCmd.RunE(installCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"repo", "add", "cnvrgv3", "https://charts.v3.cnvrg.io", "--force-update"},
+		{"repo", "update"},
+		{"upgrade", "cnvrg", "cnvrgv3/cnvrg", "-n", "cnvrg-test", "--timeout", "600s", "--wait",
+			"--values", installValues, "--install", "--create-namespace", "--version", "4.7.33"},
+	}
+	if calls := helmCalls(t, log); !reflect.DeepEqual(calls, want) {
+		t.Errorf("helm was called with\n%q\nwant\n%q", calls, want)
+	}
+}
+
+func TestInstallFailsWithHelm(t *testing.T) {
+	for _, command := range []string{"repo", "upgrade"} {
+		log := setupFakeHelm(t)
+		setupInstall(t)
+		t.Setenv("FAKE_HELM_FAIL", command)
+
+		if err := installCmd.RunE(installCmd, nil); err == nil {
+			t.Errorf("the install succeeded when helm %v failed", command)
+		}
+		calls := helmCalls(t, log)
+		if last := calls[len(calls)-1]; last[0] != command {
+			t.Errorf("helm %v was called after helm %v failed", last[0], command)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Set by the flags of the list command
+var (
+	listValues string
+	listFormat string
+)
+
+// Image hub used when the values do not set one
+const defaultImageHub = "docker.io/cnvrg"
+
+// An image deployed by the cnvrg.io Helm chart
+type componentImage struct {
+	// Name of the component, the key of its default image in the catalog
+	name string
+	// Returns true when the values deploy the component
+	enabled func(t Template) bool
+	// Optional, returns the image set in the values which replaces the default
+	override func(t Template) string
+}
+
+// Every image the cnvrg.io Helm chart can deploy
+var componentImages = []componentImage{
+	{name: "controlPlane",
+		enabled: func(t Template) bool {
+			return t.ControlPlane.WebappEnable || t.ControlPlane.SidekiqEnable || t.ControlPlane.SearchkiqEnable || t.ControlPlane.SystemkiqEnable
+		},
+		override: func(t Template) string { return t.ControlPlane.Image }},
+	{name: "hyper",
+		enabled: func(t Template) bool { return t.ControlPlane.HyperEnable }},
+	{name: "cnvrgRouter",
+		enabled:  func(t Template) bool { return t.ControlPlane.CnvrgRouterEnable },
+		override: func(t Template) string { return t.ControlPlane.CnvrgRouterImage }},
+	{name: "mpi",
+		enabled:  func(t Template) bool { return t.ControlPlane.MpiEnable },
+		override: func(t Template) string { return t.ControlPlane.MpiImage }},
+	{name: "mpiKubectlDelivery",
+		enabled:  func(t Template) bool { return t.ControlPlane.MpiEnable },
+		override: func(t Template) string { return t.ControlPlane.MpiKubectlImage }},
+	{name: "es",
+		enabled: func(t Template) bool { return t.Dbs.EsEnable }},
+	{name: "minio",
+		enabled: func(t Template) bool { return t.Dbs.MinioEnable }},
+	{name: "pg",
+		enabled: func(t Template) bool { return t.Dbs.PgEnable }},
+	{name: "redis",
+		enabled: func(t Template) bool { return t.Dbs.RedisEnable }},
+	{name: "cvat",
+		enabled: func(t Template) bool { return t.Dbs.CvatEnable }},
+	{name: "fluentbit",
+		enabled: func(t Template) bool { return t.Logging.FluentbitEnable }},
+	{name: "elastalert",
+		enabled: func(t Template) bool { return t.Logging.ElastalertEnable }},
+	{name: "kibana",
+		enabled: func(t Template) bool { return t.Logging.KibanaEnable }},
+	{name: "grafana",
+		enabled: func(t Template) bool { return t.Monitoring.GrafanaEnable }},
+	{name: "prometheusOperator",
+		enabled: func(t Template) bool { return t.Monitoring.PrometheusOperatorEnable }},
+	{name: "prometheus",
+		enabled: func(t Template) bool { return t.Monitoring.PrometheusEnable }},
+	{name: "nodeExporter",
+		enabled: func(t Template) bool { return t.Monitoring.NodeExportEnable }},
+	{name: "kubeStateMetrics",
+		enabled: func(t Template) bool { return t.Monitoring.KubeStateMetricEnable }},
+	{name: "dcgmExporter",
+		enabled: func(t Template) bool { return t.Monitoring.DcgmExportEnable && t.Gpu.NvidiaEnable }},
+	{name: "habanaExporter",
+		enabled: func(t Template) bool { return t.Monitoring.HabanaExportEnable && t.Gpu.HabanaEnable }},
+	{name: "cnvrgIdleMetricsExporter",
+		enabled: func(t Template) bool { return t.Monitoring.CnvrgIdleMetricsEnable }},
+	{name: "nvidiaDevicePlugin",
+		enabled: func(t Template) bool { return t.Gpu.NvidiaEnable }},
+	{name: "habanaDevicePlugin",
+		enabled: func(t Template) bool { return t.Gpu.HabanaEnable }},
+	{name: "capsule",
+		enabled:  func(t Template) bool { return t.Capsule.Enabled },
+		override: func(t Template) string { return t.Capsule.Image }},
+	{name: "configReloader",
+		enabled: func(t Template) bool { return t.ConfigReloader.Enabled }},
+	{name: "nfsProvisioner",
+		enabled:  func(t Template) bool { return t.Storage.Nfs.Enabled },
+		override: func(t Template) string { return t.Storage.Nfs.Image }},
+	{name: "hostpathProvisioner",
+		enabled: func(t Template) bool { return t.Storage.Hostpath.Enabled }},
+}
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the images needed by a values file",
+	Long: `List every image the configuration in the values file will deploy.
+Components disabled in the values are left out, so only the images
+which are actually deployed need to be mirrored. The images the values
+do not set are taken from the release catalog, see 'versions', for example:
+
+  cnvrg-deploy-cli images list --values values.yaml
+  cnvrg-deploy-cli images list --values values.yaml --format manifest > images.yaml
+  cnvrg-deploy-cli images load --registry registry.example.com --manifest images.yaml`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		InfoLogger.Println("In the list command")
+		t, err := readValuesFile(listValues)
+		if err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
+		images, err := requiredImages(t)
+		if err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
+		return printImages(images, listFormat)
+	},
+}
+
+func init() {
+	imagesCmd.AddCommand(listCmd)
+
+	listCmd.Flags().StringVar(&listValues, "values", "values.yaml", "values file to list the images of")
+	listCmd.Flags().StringVar(&listFormat, "format", "text", "output format [text|json|manifest]")
+	listCmd.Flags().StringVar(&catalogSource, "catalog", "", "file or URL of the release catalog the default images are taken from, the embedded catalog when empty")
+}
+
+// Returns the full reference of an image relative to the image hub, the default hub when none is set
+func hubImage(hub string, image string) string {
+	if hub == "" {
+		hub = defaultImageHub
+	}
+	return strings.TrimSuffix(hub, "/") + "/" + image
+}
+
+/*
+Returns every image deployed by the Template. The default image of a
+component is taken from the catalog, from the release of the control
+plane image or from the newest release when the image is not in the
+catalog, so the tags are the ones 'create values --release' sets.
+*/
+func requiredImages(t Template) ([]ManifestImage, error) {
+	catalog, err := loadCatalog(catalogSource)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := catalog.findByImage(t.ControlPlane.Image)
+	if !ok {
+		r = catalog.Releases[0]
+	}
+	var images []ManifestImage
+	for _, c := range componentImages {
+		if !c.enabled(t) {
+			continue
+		}
+		if c.override != nil && c.override(t) != "" {
+			images = append(images, ManifestImage{Name: c.name, Image: c.override(t)})
+			continue
+		}
+		image := r.image(c.name)
+		if image == "" {
+			return nil, fmt.Errorf("release %v of the catalog has no image for %v", r.Release, c.name)
+		}
+		images = append(images, ManifestImage{Name: c.name, Image: hubImage(t.ClusterDomain.ImageHub, image)})
+	}
+	return images, nil
+}
+
+// Prints the images in the format requested
+func printImages(images []ManifestImage, format string) error {
+	switch format {
+	case "text":
+		for _, m := range images {
+			fmt.Println(m.Image)
+		}
+	case "json":
+		out, err := json.MarshalIndent(images, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	case "manifest":
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(ImageManifest{Images: images}); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		return fmt.Errorf("unknown format %v, must be one of text, json or manifest", format)
+	}
+	return nil
+}
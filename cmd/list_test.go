@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+)
+
+// Returns the images of the Template by component name
+func imagesByName(t *testing.T, tmpl Template) map[string]string {
+	t.Helper()
+	images, err := requiredImages(tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]string{}
+	for _, m := range images {
+		byName[m.Name] = m.Image
+	}
+	return byName
+}
+
+// The components turned off in the values are left out of the images
+func TestRequiredImagesToggles(t *testing.T) {
+	toggles := map[string]func(tmpl *Template){
+		"nvidiaDevicePlugin": func(tmpl *Template) { tmpl.Gpu.NvidiaEnable = false },
+		"dcgmExporter":       func(tmpl *Template) { tmpl.Gpu.NvidiaEnable = false },
+		"grafana":            func(tmpl *Template) { tmpl.Monitoring.GrafanaEnable = false },
+		"es":                 func(tmpl *Template) { tmpl.Dbs.EsEnable = false },
+		"mpi":                func(tmpl *Template) { tmpl.ControlPlane.MpiEnable = false },
+		"mpiKubectlDelivery": func(tmpl *Template) { tmpl.ControlPlane.MpiEnable = false },
+		"capsule":            func(tmpl *Template) { tmpl.Capsule.Enabled = false },
+	}
+	defaults := imagesByName(t, defaultTemplate())
+	for name, toggle := range toggles {
+		if _, ok := defaults[name]; !ok {
+			t.Errorf("the %v image is not listed for the default values", name)
+			continue
+		}
+		tmpl := defaultTemplate()
+		toggle(&tmpl)
+		if image, ok := imagesByName(t, tmpl)[name]; ok {
+			t.Errorf("the %v image %v is listed when the component is off", name, image)
+		}
+	}
+}
+
+// The default images are the ones of the release of the control plane image in the catalog
+func TestRequiredImagesFromCatalog(t *testing.T) {
+	catalog, err := loadCatalog("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range catalog.Releases {
+		tmpl := defaultTemplate()
+		if err := applyRelease(r.Release, &tmpl); err != nil {
+			t.Fatal(err)
+		}
+		// Only the control plane image points to the release
+		tmpl.ControlPlane.MpiImage, tmpl.ControlPlane.MpiKubectlImage = "", ""
+		for name, image := range imagesByName(t, tmpl) {
+			if want := hubImage("", r.image(name)); image != want {
+				t.Errorf("the %v image of release %v is %v, want %v", name, r.Release, image, want)
+			}
+		}
+	}
+}
@@ -5,56 +5,156 @@ Copyright © 2022 NAME HERE BRADLEY.SOPER@CNVRG.IO
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Set by the flags of the load command
+var (
+	targetRegistry string
+	manifestFile   string
+	dockerBinary   string
 )
 
 // loadCmd represents the load command
 var loadCmd = &cobra.Command{
 	Use:   "load",
 	Short: "Will load a push docker images to the registry specified",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("load command called")
-		dockerPull()
+	Long: `Pull every image in the image manifest, retag it to the target
+registry and push it. Used to mirror the cnvrg.io images to a private
+registry before an install, for example:
+
+  cnvrg-deploy-cli images load --registry registry.example.com --manifest images.yaml
+
+The image manifest is a YAML file which lists the images to mirror,
+each with a name and the full image reference:
+
+  images:
+  - name: controlPlane
+    image: docker.io/cnvrg/app:v4.7.33
+  - name: mpi
+    image: docker.io/cnvrg/mpi-operator:v0.2.3
+
+Generate it from a values file with 'images list', or write it by hand:
+
+  cnvrg-deploy-cli images list --values values.yaml --format manifest > images.yaml`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		InfoLogger.Println("In the load command")
+		manifest, err := readManifest(manifestFile)
+		if err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
+		return loadImages(manifest, targetRegistry)
 	},
 }
 
 func init() {
 	imagesCmd.AddCommand(loadCmd)
 
-	// Here you will define your flags and configuration settings.
+	loadCmd.Flags().StringVar(&targetRegistry, "registry", "", "registry to push the images to")
+	loadCmd.Flags().StringVar(&manifestFile, "manifest", "images.yaml", "image manifest listing the images to load")
+	loadCmd.Flags().StringVar(&dockerBinary, "docker", "docker", "docker binary used to pull, tag and push the images")
+	loadCmd.MarkFlagRequired("registry")
+}
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// loadCmd.PersistentFlags().String("foo", "", "A help for foo")
+// Image manifest read by the images commands
+type ImageManifest struct {
+	Images []ManifestImage `yaml:"images" json:"images"`
+}
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// loadCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+// Used in the ImageManifest struct
+type ManifestImage struct {
+	Name  string `yaml:"name" json:"name"`
+	Image string `yaml:"image" json:"image"`
 }
 
-func dockerPull() {
-	cmdStr := "echo hello"
-	out, _ := exec.Command("/bin/sh", "-c", cmdStr).Output()
-	fmt.Printf("%s", out)
+// Reads the image manifest from a file
+func readManifest(name string) (ImageManifest, error) {
+	var manifest ImageManifest
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return manifest, err
+	}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("unable to parse image manifest %v: %w", name, err)
+	}
+	if len(manifest.Images) == 0 {
+		return manifest, fmt.Errorf("no images found in image manifest %v", name)
+	}
+	return manifest, nil
 }
 
-/*
-func readFile(f string) {
-	content, err := ioutil.ReadFile(f)
+// Pulls, retags and pushes every image in the manifest to the registry.
+// Every image is attempted and an error is returned if any of them failed.
+func loadImages(manifest ImageManifest, registry string) error {
+	var failed []string
 
-	if err != nil {
-		log.Fatal(err)
+	for i, m := range manifest.Images {
+		target := retagImage(m.Image, registry)
+		fmt.Printf("%v [%d/%d] %v -> %v\n", colorBlue, i+1, len(manifest.Images), m.Image, target)
+
+		err := runDocker("pull", m.Image)
+		if err == nil {
+			err = runDocker("tag", m.Image, target)
+		}
+		if err == nil {
+			err = runDocker("push", target)
+		}
+		if err != nil {
+			ErrorLogger.Printf("Failed to load %v: %v\n", m.Image, err)
+			fmt.Printf("%v   FAILED: %v\n", colorYellow, err)
+			failed = append(failed, m.Image)
+			continue
+		}
+		InfoLogger.Printf("Loaded %v to %v\n", m.Image, target)
+		fmt.Printf("%v   OK\n", colorGreen)
 	}
 
-	fmt.Println(string(content))
+	fmt.Println()
+	fmt.Printf("%v Loaded %d of %d images to %v\n", colorGreen, len(manifest.Images)-len(failed), len(manifest.Images), registry)
+	if len(failed) > 0 {
+		fmt.Println((colorYellow), "The following images failed to load:")
+		for _, image := range failed {
+			fmt.Println((colorWhite), " ", image)
+		}
+		return fmt.Errorf("%d of %d images failed to load", len(failed), len(manifest.Images))
+	}
+	return nil
+}
+
+// Returns the image name with its registry replaced by the target registry
+func retagImage(image string, registry string) string {
+	registry = strings.TrimSuffix(registry, "/")
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		image = parts[1]
+	}
+	return registry + "/" + image
+}
+
+// Runs the docker binary with the arguments and returns its output on failure
+func runDocker(args ...string) error {
+	return runCommand(dockerBinary, args...)
+}
+
+// Runs a command and returns its output as the error on failure
+func runCommand(name string, args ...string) error {
+	InfoLogger.Printf("Running %v %v\n", name, strings.Join(args, " "))
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			return err
+		}
+		return errors.New(msg)
+	}
+	return nil
 }
-*/
@@ -0,0 +1,156 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The log levels, a message is written when its level is at least the --log-level
+const (
+	levelInfo = iota
+	levelWarning
+	levelError
+)
+
+var logLevelNames = []string{"info", "warning", "error"}
+
+// Set by the persistent flags of the root command
+var (
+	logFile   string
+	logLevel  string
+	logFormat string
+	verbose   bool
+)
+
+// Loggers used by every command, nothing is written until setupLogging is called
+var (
+	InfoLogger    = newLogger(levelInfo)
+	WarningLogger = newLogger(levelWarning)
+	ErrorLogger   = newLogger(levelError)
+)
+
+// Where the loggers write to, shared by the three loggers
+var logOutput = struct {
+	sync.Mutex
+	writers []io.Writer
+	// The --log-file, closed by closeLogging
+	file  *os.File
+	level int
+	json  bool
+}{}
+
+// Where --verbose writes the logs to
+var logStderr io.Writer = os.Stderr
+
+// Matches the color codes some messages are logged with
+var colorCodes = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// Writes the entries of a logger in the configured format
+type logWriter struct {
+	level int
+}
+
+func newLogger(level int) *log.Logger {
+	return log.New(logWriter{level: level}, "", log.Lshortfile)
+}
+
+/*
+Sets where the loggers write to from the flags. Logs are only written
+when --log-file or --verbose is set, --verbose writes them to stderr.
+The log file is appended to so several runs can share it and is
+closed by closeLogging.
+*/
+func setupLogging() error {
+	level := -1
+	for i, name := range logLevelNames {
+		if name == strings.ToLower(logLevel) {
+			level = i
+		}
+	}
+	if level < 0 {
+		return fmt.Errorf("unknown log level %v, must be one of %v", logLevel, strings.Join(logLevelNames, ", "))
+	}
+	if logFormat != "text" && logFormat != "json" {
+		return fmt.Errorf("unknown log format %v, must be one of text or json", logFormat)
+	}
+
+	if err := closeLogging(); err != nil {
+		return err
+	}
+	var writers []io.Writer
+	var file *os.File
+	if logFile != "" {
+		var err error
+		file, err = os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("unable to open the log file: %w", err)
+		}
+		writers = append(writers, file)
+	}
+	if verbose {
+		writers = append(writers, logStderr)
+	}
+
+	logOutput.Lock()
+	defer logOutput.Unlock()
+	logOutput.writers = writers
+	logOutput.file = file
+	logOutput.level = level
+	logOutput.json = logFormat == "json"
+	return nil
+}
+
+// Stops the logging and closes the log file, called when the command is done
+func closeLogging() error {
+	logOutput.Lock()
+	defer logOutput.Unlock()
+	file := logOutput.file
+	logOutput.writers, logOutput.file = nil, nil
+	if file == nil {
+		return nil
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("unable to close the log file: %w", err)
+	}
+	return nil
+}
+
+// Formats a line written by the logger, which starts with the file and line it was logged from
+func (w logWriter) Write(p []byte) (int, error) {
+	logOutput.Lock()
+	defer logOutput.Unlock()
+	if len(logOutput.writers) == 0 || w.level < logOutput.level {
+		return len(p), nil
+	}
+
+	source, msg, _ := strings.Cut(strings.TrimRight(string(p), "\n"), ": ")
+	msg = strings.TrimSpace(colorCodes.ReplaceAllString(msg, ""))
+	now := time.Now().Format(time.RFC3339)
+	var entry []byte
+	if logOutput.json {
+		entry, _ = json.Marshal(struct {
+			Time   string `json:"time"`
+			Level  string `json:"level"`
+			Source string `json:"source"`
+			Msg    string `json:"msg"`
+		}{now, logLevelNames[w.level], source, msg})
+	} else {
+		entry = []byte(fmt.Sprintf("%v %-7v %v: %v", now, strings.ToUpper(logLevelNames[w.level]), source, msg))
+	}
+	entry = append(entry, '\n')
+	for _, out := range logOutput.writers {
+		out.Write(entry)
+	}
+	return len(p), nil
+}
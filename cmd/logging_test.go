@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Sets up the logging from the flags with --verbose written to a buffer
+func logToBuffer(t *testing.T, level string, format string) *bytes.Buffer {
+	t.Helper()
+	resetLogging(t)
+	buf := &bytes.Buffer{}
+	logStderr = buf
+	logLevel, logFormat, verbose = level, format, true
+	if err := setupLogging(); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+// Restores the logging flags and closes the log file after the test
+func resetLogging(t *testing.T) {
+	t.Helper()
+	oldFile, oldLevel, oldFormat, oldVerbose, oldStderr := logFile, logLevel, logFormat, verbose, logStderr
+	t.Cleanup(func() {
+		closeLogging()
+		logFile, logLevel, logFormat, verbose, logStderr = oldFile, oldLevel, oldFormat, oldVerbose, oldStderr
+	})
+}
+
+// Returns the lines written to the buffer
+func logLines(buf *bytes.Buffer) []string {
+	text := strings.TrimRight(buf.String(), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// Only the logs at or above the --log-level are written
+func TestLogLevelFiltering(t *testing.T) {
+	for _, test := range []struct {
+		level string
+		want  []string
+	}{
+		{"info", []string{"INFO", "WARNING", "ERROR"}},
+		{"warning", []string{"WARNING", "ERROR"}},
+		{"ERROR", []string{"ERROR"}},
+	} {
+		t.Run(test.level, func(t *testing.T) {
+			buf := logToBuffer(t, test.level, "text")
+			InfoLogger.Println("info message")
+			WarningLogger.Println("warning message")
+			ErrorLogger.Println("error message")
+			lines := logLines(buf)
+			if len(lines) != len(test.want) {
+				t.Fatalf("wrote %d lines, want %d:\n%v", len(lines), len(test.want), buf.String())
+			}
+			for i, level := range test.want {
+				if !strings.Contains(lines[i], " "+level+" ") {
+					t.Errorf("line %d = %q, want level %v", i, lines[i], level)
+				}
+			}
+		})
+	}
+}
+
+// A text entry has the time, level, source and message without the colors
+func TestLogTextFormat(t *testing.T) {
+	buf := logToBuffer(t, "info", "text")
+	WarningLogger.Println(colorYellow, "disk is full")
+	lines := logLines(buf)
+	if len(lines) != 1 {
+		t.Fatalf("wrote %d lines, want 1:\n%v", len(lines), buf.String())
+	}
+	entry := regexp.MustCompile(`^\S+ WARNING logging_test\.go:\d+: disk is full$`)
+	if !entry.MatchString(lines[0]) {
+		t.Errorf("entry = %q, want it to match %v", lines[0], entry)
+	}
+}
+
+// A JSON entry is one object per line with the same fields
+func TestLogJSONFormat(t *testing.T) {
+	buf := logToBuffer(t, "info", "json")
+	ErrorLogger.Println(colorYellow, "unable to connect: refused")
+	lines := logLines(buf)
+	if len(lines) != 1 {
+		t.Fatalf("wrote %d lines, want 1:\n%v", len(lines), buf.String())
+	}
+	var entry map[string]string
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("entry %q is not JSON: %v", lines[0], err)
+	}
+	if entry["level"] != "error" || entry["msg"] != "unable to connect: refused" || entry["time"] == "" {
+		t.Errorf("entry = %v", entry)
+	}
+	if !regexp.MustCompile(`^logging_test\.go:\d+$`).MatchString(entry["source"]) {
+		t.Errorf("source = %q, want logging_test.go:<line>", entry["source"])
+	}
+}
+
+// No file is written unless --log-file is set, which is appended to and closed
+func TestLogFile(t *testing.T) {
+	resetLogging(t)
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	logFile, logLevel, logFormat, verbose = "", "info", "text", false
+	if err := setupLogging(); err != nil {
+		t.Fatal(err)
+	}
+	InfoLogger.Println("not written")
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("wrote %v without --log-file", files[0].Name())
+	}
+
+	logFile = filepath.Join(dir, "cli.log")
+	os.WriteFile(logFile, []byte("earlier run\n"), 0644)
+	if err := setupLogging(); err != nil {
+		t.Fatal(err)
+	}
+	file := logOutput.file
+	InfoLogger.Println("written")
+	if err := closeLogging(); err != nil {
+		t.Fatal(err)
+	}
+	InfoLogger.Println("after close")
+	if _, err := file.Write([]byte("x")); err == nil {
+		t.Error("the log file is still open after closeLogging")
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) != 2 || lines[0] != "earlier run" || !strings.HasSuffix(lines[1], ": written") {
+		t.Errorf("log file =\n%v", string(data))
+	}
+}
+
+// An unknown level or format is an error
+func TestLogFlagsInvalid(t *testing.T) {
+	resetLogging(t)
+	logFile, verbose = "", false
+	logLevel, logFormat = "debug", "text"
+	if err := setupLogging(); err == nil {
+		t.Error("no error for the level debug")
+	}
+	logLevel, logFormat = "info", "xml"
+	if err := setupLogging(); err == nil {
+		t.Error("no error for the format xml")
+	}
+}
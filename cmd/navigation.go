@@ -0,0 +1,198 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A menu of the values command, it is shown once and returns where to go next
+type screen func() navigation
+
+// Where the menus go after a selection, the same menu is shown again when nothing is set
+type navigation struct {
+	// Shown next, the current menu is kept on the back stack
+	next screen
+	// Returns to the previous menu
+	back bool
+	// Leaves the menus
+	done bool
+}
+
+/*
+Shows the menus until one of them is done. The menus are kept on a back
+stack instead of calling each other, going back returns to the menu
+which opened the current one and the main menu is never left until the
+values are saved or the user exits. An error is returned when the
+replayed session does not match the menus.
+*/
+func runMenus(start screen) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			replay, ok := r.(replayError)
+			if !ok {
+				panic(r)
+			}
+			err = replay.err
+		}
+	}()
+	stack := []screen{start}
+	for len(stack) > 0 {
+		nav := stack[len(stack)-1]()
+		switch {
+		case nav.done:
+			return nil
+		case nav.back:
+			stack = stack[:len(stack)-1]
+		case nav.next != nil:
+			stack = append(stack, nav.next)
+		}
+	}
+	return nil
+}
+
+// A section of the values which can be edited, undone and reset from the menus
+type menuSection struct {
+	name string
+	edit func()
+	// Sets the values of the section in the Template to the defaults
+	reset func(t *Template, defaults Template)
+}
+
+// The sections of the values, in the order of the Advanced Options menu after the Cluster Domain
+var menuSections = []menuSection{
+	{"Cluster Domain", func() { gatherClusterDomain(&clusterdomain) },
+		func(t *Template, d Template) { t.ClusterDomain = d.ClusterDomain }},
+	{"Labeling", gatherLabeling,
+		func(t *Template, d Template) {
+			t.Labels, t.Annotations, t.ClusterInteralDomain = d.Labels, d.Annotations, d.ClusterInteralDomain
+		}},
+	{"Networking", func() { gatherNetworking(&network) },
+		func(t *Template, d Template) { t.Network = d.Network }},
+	{"Logging", func() { gatherLogging(&logging) },
+		func(t *Template, d Template) { t.Logging = d.Logging }},
+	{"Registry", func() { gatherRegistry(&registry) },
+		func(t *Template, d Template) { t.Registry = d.Registry }},
+	{"Tenancy", func() { gatherTenancy(&tenancy) },
+		func(t *Template, d Template) { t.Tenancy = d.Tenancy }},
+	{"Single Sign On", func() { gatherSso(&sso) },
+		func(t *Template, d Template) { t.Sso = d.Sso }},
+	{"Storage", func() { gatherStorage(&storage) },
+		func(t *Template, d Template) { t.Storage = d.Storage }},
+	{"Miscellaneous", gatherMisc,
+		func(t *Template, d Template) {
+			t.Backup, t.Capsule, t.Gpu, t.ConfigReloader = d.Backup, d.Capsule, d.Gpu, d.ConfigReloader
+		}},
+	{"Monitoring", func() { gatherMonitoring(&monitoring) },
+		func(t *Template, d Template) { t.Monitoring = d.Monitoring }},
+	{"Control Plane", func() { gatherControlPlane(&controlplane) },
+		func(t *Template, d Template) { t.ControlPlane = d.ControlPlane }},
+	{"Databases", func() { gatherDbs(&dbs) },
+		func(t *Template, d Template) { t.Dbs = d.Dbs }},
+}
+
+// A change made in the menus, the values from before it are kept to undo it
+type change struct {
+	name   string
+	before Template
+}
+
+// The changes made in the menus, the last one is undone first
+var changes []change
+
+// Shows the menu of the section and keeps the values from before it so the change can be undone
+func editSection(s menuSection) {
+	before := copyTemplate(currentTemplate())
+	s.edit()
+	recordChange("the "+s.name+" settings", before)
+}
+
+// Adds a change when the values differ from the values before it
+func recordChange(name string, before Template) {
+	if reflect.DeepEqual(before, currentTemplate()) {
+		return
+	}
+	changes = append(changes, change{name: name, before: before})
+	InfoLogger.Printf("Changed %v\n", name)
+}
+
+// Sets the values back to before the last change
+func undoChange() {
+	if len(changes) == 0 {
+		fmt.Println((colorYellow), "There are no changes to undo")
+		return
+	}
+	last := changes[len(changes)-1]
+	changes = changes[:len(changes)-1]
+	applyTemplate(last.before)
+	fmt.Printf("%v Undid the change to %v\n", colorGreen, last.name)
+}
+
+// Prompts for a section and sets its values back to the defaults, the reset can be undone
+func resetSection() {
+	fmt.Println()
+	fmt.Println((colorGreen), "Please select the section to reset to its defaults")
+	for i, s := range menuSections {
+		fmt.Printf("%v Press '%d' for %v\n", colorBlue, i+1, s.name)
+	}
+	fmt.Printf("%v Press '%d' to return without a reset\n", colorBlue, len(menuSections)+1)
+	intVar := promptMenu("Reset Section Menu", len(menuSections)+1)
+	if intVar > len(menuSections) {
+		return
+	}
+	s := menuSections[intVar-1]
+	if !promptConfirm(fmt.Sprintf("Reset the %v settings to their defaults? [y/N]: ", s.name)) {
+		return
+	}
+	before := copyTemplate(currentTemplate())
+	t := currentTemplate()
+	s.reset(&t, defaultTemplate())
+	applyTemplate(copyTemplate(t))
+	recordChange("the "+s.name+" settings by the reset", before)
+	fmt.Printf("%v Reset the %v settings to their defaults\n", colorGreen, s.name)
+}
+
+// Returns a copy of the Template which shares no maps or slices, the menus change the maps in place
+func copyTemplate(t Template) Template {
+	var c Template
+	copyValue(reflect.ValueOf(&c).Elem(), reflect.ValueOf(t))
+	return c
+}
+
+func copyValue(dst reflect.Value, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			copyValue(dst.Field(i), src.Field(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), iter.Value())
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+		reflect.Copy(dst, src)
+	default:
+		dst.Set(src)
+	}
+}
+
+// Asks before the changes made in the menus are dropped, returns true when the menus can be left
+func confirmExit() bool {
+	if len(changes) > 0 && !promptConfirm(fmt.Sprintf("Exit and drop the %d unsaved change(s)? (yes/no) [no]: ", len(changes))) {
+		return false
+	}
+	fmt.Println((colorYellow), "Exiting without writing the values")
+	return true
+}
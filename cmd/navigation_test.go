@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+)
+
+// The main menu exits right away without changes and asks before dropping them
+func TestMainMenuExit(t *testing.T) {
+	resetSession(t)
+	setInput(t, "6")
+	if err := runMenus(mainMenu); err != nil {
+		t.Fatal(err)
+	}
+
+	before := currentTemplate()
+	clusterdomain.ClusterDomain = "example.com"
+	recordChange("Cluster Domain", before)
+	// Not confirmed the first time, the main menu is shown again
+	setInput(t, "6", "no", "6", "yes")
+	if err := runMenus(mainMenu); err != nil {
+		t.Fatal(err)
+	}
+	if rest, _ := stdin.ReadString('\n'); rest != "" {
+		t.Errorf("the menus were left with the input %q left", rest)
+	}
+	if len(changes) != 1 {
+		t.Errorf("%d changes are kept, want 1", len(changes))
+	}
+}
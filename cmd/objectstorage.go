@@ -0,0 +1,232 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// A value of the object storage, the name is its key in the values file
+type objectStorageField struct {
+	name   string
+	label  string
+	secret bool
+	field  func(cp *ControlPlane) *string
+	// Checks the value typed in the menu, nil accepts any value
+	check func(string) error
+}
+
+// Every object storage value other than the type
+var objectStorageFields = []objectStorageField{
+	{name: "bucket", label: "Bucket",
+		field: func(cp *ControlPlane) *string { return &cp.ObjectStorageBucket }},
+	{name: "region", label: "Region",
+		field: func(cp *ControlPlane) *string { return &cp.ObjectStorageRegion }},
+	{name: "accessKey", label: "Access Key",
+		field: func(cp *ControlPlane) *string { return &cp.ObjectStorageAccessKey }},
+	{name: "secretKey", label: "Secret Key", secret: true,
+		field: func(cp *ControlPlane) *string { return &cp.ObjectStorageSecretKey }},
+	{name: "endpoint", label: "Endpoint URL", check: checkURL,
+		field: func(cp *ControlPlane) *string { return &cp.ObjectStorageEndpoint }},
+	{name: "azureAccountName", label: "Azure Storage Account Name",
+		field: func(cp *ControlPlane) *string { return &cp.ObjectStorageAzureAcountName }},
+	{name: "azureContainer", label: "Azure Container",
+		field: func(cp *ControlPlane) *string { return &cp.ObjectStorageAzureContainer }},
+	{name: "gcpSecretRef", label: "GCP Service Account Secret",
+		field: func(cp *ControlPlane) *string { return &cp.ObjectStorageGcpSecretRef }},
+	{name: "gcpProject", label: "GCP Project",
+		field: func(cp *ControlPlane) *string { return &cp.ObjectStorageGcpProject }},
+}
+
+// Returns the object storage value with the name and false when there is none
+func findObjectStorageField(name string) (objectStorageField, bool) {
+	for _, f := range objectStorageFields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return objectStorageField{}, false
+}
+
+// An object storage type and the values it uses
+type objectStorageProvider struct {
+	name     string
+	fields   []string
+	required []string
+}
+
+// The supported object storage types, in the order they are offered in the menu
+var objectStorageProviders = []objectStorageProvider{
+	{name: "minio", fields: []string{"bucket", "region", "accessKey", "secretKey", "endpoint"}},
+	{name: "aws", fields: []string{"bucket", "region", "accessKey", "secretKey", "endpoint"},
+		required: []string{"bucket", "region"}},
+	{name: "azure", fields: []string{"azureAccountName", "azureContainer", "secretKey"},
+		required: []string{"azureAccountName", "azureContainer", "secretKey"}},
+	{name: "gcp", fields: []string{"bucket", "gcpProject", "gcpSecretRef"},
+		required: []string{"bucket", "gcpProject", "gcpSecretRef"}},
+}
+
+// Returns the provider of the object storage type
+func findObjectStorageProvider(name string) (objectStorageProvider, bool) {
+	for _, p := range objectStorageProviders {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return objectStorageProvider{}, false
+}
+
+// Returns true when the provider uses the value
+func (p objectStorageProvider) uses(field string) bool {
+	for _, f := range p.fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the names of the object storage types
+func objectStorageTypes() []string {
+	var names []string
+	for _, p := range objectStorageProviders {
+		names = append(names, p.name)
+	}
+	return names
+}
+
+// Adds an error for every object storage value which is missing or not used by the type
+func validateObjectStorage(cp ControlPlane, add func(string, string, ...interface{})) {
+	if cp.ObjectStorageType == "" {
+		for _, f := range objectStorageFields {
+			if *f.field(&cp) != "" {
+				add("controlPlane.objectStorage.type", "is required when object storage values are set")
+				return
+			}
+		}
+		return
+	}
+	p, ok := findObjectStorageProvider(cp.ObjectStorageType)
+	if !ok {
+		add("controlPlane.objectStorage.type", "must be one of %v, got %q", strings.Join(objectStorageTypes(), ", "), cp.ObjectStorageType)
+		return
+	}
+	for _, f := range objectStorageFields {
+		if *f.field(&cp) != "" && !p.uses(f.name) {
+			add("controlPlane.objectStorage."+f.name, "is not used with the %v object storage type", p.name)
+		}
+	}
+	for _, name := range p.required {
+		f, ok := findObjectStorageField(name)
+		if !ok {
+			add("controlPlane.objectStorage."+name, "is not a known object storage value")
+			continue
+		}
+		if *f.field(&cp) == "" {
+			add("controlPlane.objectStorage."+f.name, "is required with the %v object storage type", p.name)
+		}
+	}
+}
+
+/*
+Checks an S3 compatible endpoint can be reached and the bucket can be
+accessed with the keys by sending a signed HEAD request for the bucket.
+Used for the aws and minio object storage types.
+*/
+func probeObjectStorage(cp ControlPlane) error {
+	if cp.ObjectStorageType != "aws" && cp.ObjectStorageType != "minio" {
+		return fmt.Errorf("only S3 compatible object storage can be tested, the type is %q", cp.ObjectStorageType)
+	}
+	if _, ok := parseSecretRef(cp.ObjectStorageSecretKey); ok {
+		return fmt.Errorf("the secret key is stored in a Secret and can not be tested")
+	}
+	region := cp.ObjectStorageRegion
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := strings.TrimSuffix(cp.ObjectStorageEndpoint, "/")
+	if endpoint == "" {
+		if cp.ObjectStorageType == "minio" {
+			return fmt.Errorf("an endpoint is required to test minio object storage")
+		}
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	if cp.ObjectStorageBucket == "" {
+		return fmt.Errorf("a bucket is required to test the object storage")
+	}
+
+	url := endpoint + "/" + cp.ObjectStorageBucket
+	InfoLogger.Printf("Testing the object storage bucket %v\n", url)
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	if cp.ObjectStorageAccessKey != "" {
+		signS3Request(req, cp.ObjectStorageAccessKey, cp.ObjectStorageSecretKey, region, time.Now().UTC())
+	}
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach the object storage endpoint %v: %w", endpoint, err)
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("the bucket %v does not exist", cp.ObjectStorageBucket)
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return fmt.Errorf("access to the bucket %v was denied, check the keys", cp.ObjectStorageBucket)
+	}
+	return fmt.Errorf("the object storage endpoint returned %v", resp.Status)
+}
+
+// Signs a request with the AWS signature version 4 for the s3 service
+func signS3Request(req *http.Request, accessKey string, secretKey string, region string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := hex.EncodeToString(sha256Sum(""))
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(sha256Sum(canonicalRequest))
+
+	key := hmacSum([]byte("AWS4"+secretKey), date)
+	key = hmacSum(key, region)
+	key = hmacSum(key, "s3")
+	key = hmacSum(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSum(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func sha256Sum(data string) []byte {
+	sum := sha256.Sum256([]byte(data))
+	return sum[:]
+}
+
+func hmacSum(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import "testing"
+
+// Every value a provider uses or requires must be in objectStorageFields
+func TestObjectStorageProviderFields(t *testing.T) {
+	for _, p := range objectStorageProviders {
+		for _, name := range append(p.fields, p.required...) {
+			if _, ok := findObjectStorageField(name); !ok {
+				t.Errorf("the %v provider uses the unknown value %q", p.name, name)
+			}
+		}
+		for _, name := range p.required {
+			if !p.uses(name) {
+				t.Errorf("the %v provider requires %q but does not prompt for it", p.name, name)
+			}
+		}
+	}
+}
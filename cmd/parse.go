@@ -0,0 +1,339 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A parsed values file, used to read the values back into the Template
+type valuesMap map[string]interface{}
+
+// Returns the value at the path of keys in the values file
+func (v valuesMap) lookup(path ...string) (interface{}, bool) {
+	var current interface{} = map[string]interface{}(v)
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// Sets dst to the string found at the path if it exists
+func (v valuesMap) str(dst *string, path ...string) {
+	if value, ok := v.lookup(path...); ok && value != nil {
+		*dst = fmt.Sprint(value)
+	}
+}
+
+// Sets dst to the bool found at the path if it exists
+func (v valuesMap) boolean(dst *bool, path ...string) {
+	if value, ok := v.lookup(path...); ok {
+		switch b := value.(type) {
+		case bool:
+			*dst = b
+		case string:
+			*dst, _ = strconv.ParseBool(b)
+		}
+	}
+}
+
+// Sets dst to the int found at the path if it exists
+func (v valuesMap) integer(dst *int, path ...string) {
+	if value, ok := v.lookup(path...); ok {
+		switch i := value.(type) {
+		case int:
+			*dst = i
+		case string:
+			*dst, _ = strconv.Atoi(i)
+		}
+	}
+}
+
+// Sets dst to the list found at the path if it exists
+func (v valuesMap) list(dst *[]string, path ...string) {
+	if value, ok := v.lookup(path...); ok && value != nil {
+		*dst = nil
+		switch l := value.(type) {
+		case []interface{}:
+			for _, item := range l {
+				*dst = append(*dst, fmt.Sprint(item))
+			}
+		default:
+			*dst = append(*dst, fmt.Sprint(l))
+		}
+	}
+}
+
+// Sets dst to the map found at the path if it exists
+func (v valuesMap) dict(dst *map[string]string, path ...string) {
+	if value, ok := v.lookup(path...); ok {
+		if m, ok := value.(map[string]interface{}); ok {
+			*dst = map[string]string{}
+			for key, item := range m {
+				(*dst)[key] = fmt.Sprint(item)
+			}
+		}
+	}
+}
+
+// Sets dst to a reference to the Secret found at the path if it exists
+func (v valuesMap) secretRef(dst *string, path ...string) {
+	var ref map[string]string
+	v.dict(&ref, path...)
+	if ref["name"] != "" && ref["key"] != "" {
+		*dst = formatSecretRef(ref["name"], ref["key"])
+	}
+}
+
+/*
+Reads the values files and returns them as a Template. Like Helm with
+several --values files, the values of a later file override the ones
+before it, which is how the secrets file is merged into the values.
+*/
+func readValuesFile(names ...string) (Template, error) {
+	data, err := readMergedValues(names...)
+	if err != nil {
+		return Template{}, err
+	}
+	t, err := parseValues(data)
+	if err != nil {
+		return Template{}, fmt.Errorf("unable to parse values file %v: %w", strings.Join(names, ", "), err)
+	}
+	return t, nil
+}
+
+// Reads the values files and merges them into a single values file
+func readMergedValues(names ...string) ([]byte, error) {
+	InfoLogger.Printf("Reading the values file %v\n", strings.Join(names, ", "))
+	if len(names) == 1 {
+		return os.ReadFile(names[0])
+	}
+	merged := map[string]interface{}{}
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		m := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("unable to parse values file %v: %w", name, err)
+		}
+		mergeValues(merged, m)
+	}
+	return yaml.Marshal(merged)
+}
+
+// Merges the values of src into dst, mappings found in both are merged key by key
+func mergeValues(dst map[string]interface{}, src map[string]interface{}) {
+	for key, value := range src {
+		if s, ok := value.(map[string]interface{}); ok {
+			if d, ok := dst[key].(map[string]interface{}); ok {
+				mergeValues(d, s)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
+
+// Parses the contents of a values file into a Template. Any value
+// missing from the file keeps the default from defaultTemplate.
+func parseValues(data []byte) (Template, error) {
+	t := defaultTemplate()
+	m := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return t, err
+	}
+	v := valuesMap(m)
+
+	v.str(&t.ClusterDomain.ClusterDomain, "clusterDomain")
+	v.str(&t.ClusterInteralDomain.Domain, "clusterInternalDomain")
+	v.str(&t.ClusterDomain.ImageHub, "imageHub")
+	v.dict((*map[string]string)(&t.Labels), "labels")
+	v.dict((*map[string]string)(&t.Annotations), "annotations")
+
+	// Networking
+	v.boolean(&t.Network.Https.Enabled, "networking", "https", "enabled")
+	v.str(&t.Network.Https.CertSecret, "networking", "https", "certSecret")
+	v.boolean(&t.Network.Proxy.Enabled, "networking", "proxy", "enabled")
+	v.list(&t.Network.Proxy.HttpProxy, "networking", "proxy", "httpProxy")
+	v.list(&t.Network.Proxy.HttpsProxy, "networking", "proxy", "httpsProxy")
+	v.list(&t.Network.Proxy.NoProxy, "networking", "proxy", "noProxy")
+	v.str(&t.Network.Ingress.Type, "networking", "ingress", "type")
+	v.boolean(&t.Network.Ingress.IstioGwEnabled, "networking", "ingress", "istioGwEnabled")
+	v.str(&t.Network.Ingress.IstioGwName, "networking", "ingress", "istioGwName")
+	v.boolean(&t.Network.Ingress.External, "networking", "ingress", "external")
+	v.boolean(&t.Network.Istio.Enabled, "networking", "istio", "enabled")
+	v.list(&t.Network.Istio.ExternalIp, "networking", "istio", "externalIp")
+	v.dict(&t.Network.Istio.IngressSvcAnnotations, "networking", "istio", "ingressSvcAnnotations")
+	v.list(&t.Network.Istio.IngressSvcExtraPorts, "networking", "istio", "ingressSvcExtraPorts")
+	v.list(&t.Network.Istio.LbSourceRanges, "networking", "istio", "lbSourceRanges")
+
+	// Logging
+	v.boolean(&t.Logging.FluentbitEnable, "logging", "fluentbit", "enabled")
+	v.boolean(&t.Logging.ElastalertEnable, "logging", "elastalert", "enabled")
+	v.str(&t.Logging.ElastaStorageSize, "logging", "elastalert", "storageSize")
+	v.str(&t.Logging.ElastaStorageClass, "logging", "elastalert", "storageClass")
+	v.dict(&t.Logging.ElastaNodeSelector, "logging", "elastalert", "nodeSelector")
+	v.boolean(&t.Logging.KibanaEnable, "logging", "kibana", "enabled")
+	v.str(&t.Logging.KibanaSvcName, "logging", "kibana", "svcName")
+
+	// Registry
+	v.str(&t.Registry.Url, "registry", "url")
+	v.str(&t.Registry.User, "registry", "user")
+	v.str(&t.Registry.Password, "registry", "password")
+	t.Registry.Enabled = t.Registry.Url != "" || t.Registry.User != "" || t.Registry.Password != ""
+
+	// Tenancy
+	v.boolean(&t.Tenancy.Enabled, "tenancy", "enabled")
+	v.str(&t.Tenancy.Key, "tenancy", "key")
+	v.str(&t.Tenancy.Value, "tenancy", "value")
+
+	// Single Sign On
+	v.boolean(&t.Sso.Enabled, "sso", "enabled")
+	v.str(&t.Sso.AdminUser, "sso", "adminUser")
+	v.str(&t.Sso.Provider, "sso", "provider")
+	v.list(&t.Sso.EmailDomain, "sso", "emailDomain")
+	v.str(&t.Sso.ClientId, "sso", "clientId")
+	v.str(&t.Sso.ClientSecret, "sso", "clientSecret")
+	v.str(&t.Sso.AzureTenant, "sso", "azureTenant")
+	v.str(&t.Sso.OidcIssuerUrl, "sso", "oidcIssuerUrl")
+
+	// Storage
+	v.boolean(&t.Storage.Nfs.Enabled, "storage", "nfs", "enabled")
+	v.str(&t.Storage.Nfs.Server, "storage", "nfs", "server")
+	v.str(&t.Storage.Nfs.Path, "storage", "nfs", "path")
+	v.boolean(&t.Storage.Nfs.DefaultSc, "storage", "nfs", "defaultSc")
+	v.str(&t.Storage.Nfs.ReclaimPolicy, "storage", "nfs", "reclaimPolicy")
+	v.str(&t.Storage.Nfs.Image, "storage", "nfs", "image")
+	v.boolean(&t.Storage.Hostpath.Enabled, "storage", "hostpath", "enabled")
+	v.boolean(&t.Storage.Hostpath.DefaultSc, "storage", "hostpath", "defaultSc")
+	v.str(&t.Storage.Hostpath.Path, "storage", "hostpath", "path")
+	v.str(&t.Storage.Hostpath.ReclaimPolicy, "storage", "hostpath", "reclaimPolicy")
+	v.dict(&t.Storage.Hostpath.NodeSelector, "storage", "hostpath", "nodeSelector")
+
+	// Miscellaneous
+	v.boolean(&t.Gpu.NvidiaEnable, "gpu", "nvidiaDp", "enabled")
+	v.boolean(&t.Gpu.HabanaEnable, "gpu", "habanaDp", "enabled")
+	v.boolean(&t.ConfigReloader.Enabled, "configReloader", "enabled")
+	v.boolean(&t.Capsule.Enabled, "capsule", "enabled")
+	v.str(&t.Capsule.Image, "capsule", "image")
+	v.boolean(&t.Backup.Enabled, "backup", "enabled")
+	v.integer(&t.Backup.Rotation, "backup", "rotation")
+	v.str(&t.Backup.Period, "backup", "period")
+
+	// Monitoring
+	v.boolean(&t.Monitoring.DcgmExportEnable, "monitoring", "dcgmExporter", "enabled")
+	v.boolean(&t.Monitoring.HabanaExportEnable, "monitoring", "habanaExporter", "enabled")
+	v.boolean(&t.Monitoring.NodeExportEnable, "monitoring", "nodeExporter", "enabled")
+	v.boolean(&t.Monitoring.KubeStateMetricEnable, "monitoring", "kubeStateMetrics", "enabled")
+	v.boolean(&t.Monitoring.GrafanaEnable, "monitoring", "grafana", "enabled")
+	v.str(&t.Monitoring.GrafanaSvcName, "monitoring", "grafana", "svcName")
+	v.boolean(&t.Monitoring.PrometheusOperatorEnable, "monitoring", "prometheusOperator", "enabled")
+	v.boolean(&t.Monitoring.PrometheusEnable, "monitoring", "prometheus", "enabled")
+	v.str(&t.Monitoring.PrometheusStorageSize, "monitoring", "prometheus", "storageSize")
+	v.str(&t.Monitoring.PrometheusStorageClass, "monitoring", "prometheus", "storageClass")
+	v.dict(&t.Monitoring.PrometheusNodeSelector, "monitoring", "prometheus", "nodeSelector")
+	v.boolean(&t.Monitoring.DefaultSvcMonitorsEnable, "monitoring", "defaultServiceMonitors", "enabled")
+	v.boolean(&t.Monitoring.CnvrgIdleMetricsEnable, "monitoring", "cnvrgIdleMetricsExporter", "enabled")
+	v.dict(&t.Monitoring.CnvrgIdleMetricsLabels, "monitoring", "cnvrgIdleMetricsExporter", "labels")
+
+	// Databases
+	v.boolean(&t.Dbs.CvatEnable, "dbs", "cvat", "enabled")
+	v.boolean(&t.Dbs.EsEnable, "dbs", "es", "enabled")
+	v.str(&t.Dbs.EsStorageSize, "dbs", "es", "storageSize")
+	v.str(&t.Dbs.EsStorageClass, "dbs", "es", "storageClass")
+	v.boolean(&t.Dbs.EsPatchNodes, "dbs", "es", "patchEsNodes")
+	v.dict(&t.Dbs.EsNodeSelector, "dbs", "es", "nodeSelector")
+	v.str(&t.Dbs.CleanUpAll, "dbs", "es", "cleanupPolicy", "all")
+	v.str(&t.Dbs.CleanUpApp, "dbs", "es", "cleanupPolicy", "app")
+	v.str(&t.Dbs.CleanUpJobs, "dbs", "es", "cleanupPolicy", "jobs")
+	v.str(&t.Dbs.CleanUpEndpoints, "dbs", "es", "cleanupPolicy", "endpoints")
+	v.boolean(&t.Dbs.MinioEnable, "dbs", "minio", "enabled")
+	v.str(&t.Dbs.MinioStorageSize, "dbs", "minio", "storageSize")
+	v.str(&t.Dbs.MinioStorageClass, "dbs", "minio", "storageClass")
+	v.dict(&t.Dbs.MinioNodeSelector, "dbs", "minio", "nodeSelector")
+	v.boolean(&t.Dbs.PgEnable, "dbs", "pg", "enabled")
+	v.str(&t.Dbs.PgStorageSize, "dbs", "pg", "storageSize")
+	v.str(&t.Dbs.PgStorageClass, "dbs", "pg", "storageClass")
+	v.dict(&t.Dbs.PgNodeSelector, "dbs", "pg", "nodeSelector")
+	v.boolean(&t.Dbs.PgPagesEnable, "dbs", "pg", "hugePages", "enabled")
+	v.str(&t.Dbs.PgPagesSize, "dbs", "pg", "hugePages", "size")
+	v.str(&t.Dbs.PgPagesMemory, "dbs", "pg", "hugePages", "memory")
+	v.boolean(&t.Dbs.RedisEnable, "dbs", "redis", "enabled")
+	v.str(&t.Dbs.RedisStorageSize, "dbs", "redis", "storageSize")
+	v.str(&t.Dbs.RedisStorageClass, "dbs", "redis", "storageClass")
+	v.dict(&t.Dbs.RedisNodeSelector, "dbs", "redis", "nodeSelector")
+
+	// Control Plane
+	v.str(&t.ControlPlane.Image, "controlPlane", "image")
+	v.str(&t.ControlPlane.BaseConfigAgentTag, "controlPlane", "baseConfig", "agentCustomTag")
+	v.boolean(&t.ControlPlane.BaseConfigIntercom, "controlPlane", "baseConfig", "intercom")
+	v.dict(&t.ControlPlane.BaseConfigFeatureFlags, "controlPlane", "baseConfig", "featureFlags")
+	v.boolean(&t.ControlPlane.BaseConfigCnvrgPrivileged, "controlPlane", "baseConfig", "cnvrgPrivilegedJob")
+	v.boolean(&t.ControlPlane.HyperEnable, "controlPlane", "hyper", "enabled")
+	v.boolean(&t.ControlPlane.CnvrgScheduleEnable, "controlPlane", "cnvrgScheduler", "enabled")
+	v.boolean(&t.ControlPlane.CnvrgClusterProvisionerEnable, "controlPlane", "cnvrgClusterProvisionerOperator", "enabled")
+	v.str(&t.ControlPlane.ObjectStorageType, "controlPlane", "objectStorage", "type")
+	v.str(&t.ControlPlane.ObjectStorageBucket, "controlPlane", "objectStorage", "bucket")
+	v.str(&t.ControlPlane.ObjectStorageRegion, "controlPlane", "objectStorage", "region")
+	v.str(&t.ControlPlane.ObjectStorageAccessKey, "controlPlane", "objectStorage", "accessKey")
+	v.str(&t.ControlPlane.ObjectStorageSecretKey, "controlPlane", "objectStorage", "secretKey")
+	v.str(&t.ControlPlane.ObjectStorageEndpoint, "controlPlane", "objectStorage", "endpoint")
+	v.str(&t.ControlPlane.ObjectStorageAzureAcountName, "controlPlane", "objectStorage", "azureAccountName")
+	v.str(&t.ControlPlane.ObjectStorageAzureContainer, "controlPlane", "objectStorage", "azureContainer")
+	v.str(&t.ControlPlane.ObjectStorageGcpSecretRef, "controlPlane", "objectStorage", "gcpSecretRef")
+	v.str(&t.ControlPlane.ObjectStorageGcpProject, "controlPlane", "objectStorage", "gcpProject")
+	v.boolean(&t.ControlPlane.SearchkiqEnable, "controlPlane", "searchkiq", "enabled")
+	v.boolean(&t.ControlPlane.SearchkiqHpaEnable, "controlPlane", "searchkiq", "hpa", "enabled")
+	v.integer(&t.ControlPlane.SearchkiqHpaMaxReplicas, "controlPlane", "searchkiq", "hpa", "maxReplicas")
+	v.boolean(&t.ControlPlane.SidekiqEnable, "controlPlane", "sidekiq", "enabled")
+	v.boolean(&t.ControlPlane.SidekiqSplit, "controlPlane", "sidekiq", "split")
+	v.boolean(&t.ControlPlane.SidekiqHpaEnable, "controlPlane", "sidekiq", "hpa", "enabled")
+	v.integer(&t.ControlPlane.SidekiqHpaMaxReplicas, "controlPlane", "sidekiq", "hpa", "maxReplicas")
+	v.boolean(&t.ControlPlane.CnvrgRouterEnable, "controlPlane", "cnvrgRouter", "enabled")
+	v.str(&t.ControlPlane.CnvrgRouterImage, "controlPlane", "cnvrgRouter", "image")
+	v.str(&t.ControlPlane.SmtpServer, "controlPlane", "smtp", "server")
+	v.integer(&t.ControlPlane.SmtpPort, "controlPlane", "smtp", "port")
+	v.str(&t.ControlPlane.SmtpUsername, "controlPlane", "smtp", "username")
+	v.str(&t.ControlPlane.SmtpPassword, "controlPlane", "smtp", "password")
+	v.str(&t.ControlPlane.SmtpDomain, "controlPlane", "smtp", "domain")
+	v.str(&t.ControlPlane.SmtpOpenSslMode, "controlPlane", "smtp", "opensslVerifyMode")
+	v.str(&t.ControlPlane.SmtpSender, "controlPlane", "smtp", "sender")
+	v.boolean(&t.ControlPlane.SystemkiqEnable, "controlPlane", "systemkiq", "enabled")
+	v.boolean(&t.ControlPlane.SystemkiqHpaEnable, "controlPlane", "systemkiq", "hpa", "enabled")
+	v.integer(&t.ControlPlane.SystemkiqHpaMaxReplicas, "controlPlane", "systemkiq", "hpa", "maxReplicas")
+	v.boolean(&t.ControlPlane.WebappEnable, "controlPlane", "webapp", "enabled")
+	v.str(&t.ControlPlane.WebappSvcName, "controlPlane", "webapp", "svcName")
+	v.integer(&t.ControlPlane.WebappReplicas, "controlPlane", "webapp", "replicas")
+	v.boolean(&t.ControlPlane.WebappHpaEnable, "controlPlane", "webapp", "hpa", "enabled")
+	v.integer(&t.ControlPlane.WebappHpaMaxReplicas, "controlPlane", "webapp", "hpa", "maxReplicas")
+	v.boolean(&t.ControlPlane.MpiEnable, "controlPlane", "mpi", "enabled")
+	v.str(&t.ControlPlane.MpiImage, "controlPlane", "mpi", "image")
+	v.str(&t.ControlPlane.MpiKubectlImage, "controlPlane", "mpi", "kubectlDeliveryImage")
+	v.dict(&t.ControlPlane.MpiExtraArgs, "controlPlane", "mpi", "extraArgs")
+	v.str(&t.ControlPlane.MpiRegistryUrl, "controlPlane", "mpi", "registry", "url")
+	v.str(&t.ControlPlane.MpiRegistryUser, "controlPlane", "mpi", "registry", "user")
+	v.str(&t.ControlPlane.MpiRegistryPassword, "controlPlane", "mpi", "registry", "password")
+
+	// Secret values written as references to a Secret
+	for _, s := range secretFields {
+		v.secretRef(s.field(&t), strings.Split(s.ref, ".")...)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Sets every value of the struct to something other than its default
+func fillValues(t *testing.T, v reflect.Value) {
+	fill(t, v)
+	// Not a value of the chart, it is never written to the values file
+	v.FieldByName("ClusterDomain").FieldByName("Spec").SetString("")
+}
+
+func fill(t *testing.T, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fill(t, v.Field(i))
+		}
+	case reflect.String:
+		v.SetString("value-" + v.String())
+	case reflect.Int:
+		v.SetInt(v.Int() + 3)
+	case reflect.Bool:
+		v.SetBool(!v.Bool())
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		m.SetMapIndex(reflect.ValueOf("key"), reflect.ValueOf("value"))
+		v.Set(m)
+	case reflect.Slice:
+		v.Set(reflect.Append(reflect.MakeSlice(v.Type(), 0, 1), reflect.ValueOf("value")))
+	default:
+		t.Fatalf("unable to fill a %v value", v.Kind())
+	}
+}
+
+// Fails for every value which was not read back as it was rendered
+func compareTemplates(t *testing.T, name string, got Template, want Template) {
+	t.Helper()
+	for j := 0; j < reflect.ValueOf(want).NumField(); j++ {
+		field := reflect.TypeOf(want).Field(j).Name
+		diffTemplateValues(field, reflect.ValueOf(got).Field(j), reflect.ValueOf(want).Field(j), func(path string, value reflect.Value, def reflect.Value) {
+			t.Errorf("%v: %v is %v after parsing, rendered %v", name, path, formatValue(value), formatValue(def))
+		})
+	}
+}
+
+// Every value written to the values file is read back into the same Template
+func TestParseRenderedValues(t *testing.T) {
+	templates := map[string]Template{}
+	for _, p := range builtinProfiles {
+		tmpl := defaultTemplate()
+		if err := p.apply(&tmpl); err != nil {
+			t.Fatal(err)
+		}
+		tmpl.ClusterDomain.ClusterDomain = "example.com"
+		if tmpl.Registry.Enabled {
+			tmpl.Registry.Url = "registry.example.com"
+		}
+		templates[p.name] = tmpl
+	}
+	full := defaultTemplate()
+	fillValues(t, reflect.ValueOf(&full).Elem())
+	templates["every value"] = full
+
+	for name, tmpl := range templates {
+		data, err := renderValues(&tmpl)
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		parsed, err := parseValues(data)
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		compareTemplates(t, name, parsed, tmpl)
+	}
+}
+
+// The secrets file holds the secret values left out of the values file
+func TestReadValuesWithSecretsFile(t *testing.T) {
+	defer func(mode string) { secretsMode = mode }(secretsMode)
+	secretsMode = secretsSeparate
+
+	tmpl := defaultTemplate()
+	fillValues(t, reflect.ValueOf(&tmpl).Elem())
+	dir := t.TempDir()
+	values := filepath.Join(dir, "values.yaml")
+	secrets := filepath.Join(dir, "secrets.yaml")
+	if err := createFile(values, &tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if err := createSecretsFile(secrets, &tmpl); err != nil {
+		t.Fatal(err)
+	}
+
+	withoutSecrets, err := readValuesFile(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range secretFields {
+		if value := *s.field(&withoutSecrets); value != "" {
+			t.Errorf("%v is written to the values file as %q", s.path, value)
+		}
+	}
+	merged, err := readValuesFile(values, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareTemplates(t, "merged", merged, tmpl)
+
+	info, err := os.Stat(secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("the secrets file is created with the mode %v", info.Mode().Perm())
+	}
+}
+
+// The registry is only enabled when the values file sets any of its values
+func TestParseRegistryEnabled(t *testing.T) {
+	for data, want := range map[string]bool{
+		"registry:\n":                     false,
+		"registry: {}\n":                  false,
+		"registry:\n  url: example.com\n": true,
+		"registry:\n  user: admin\n":      true,
+	} {
+		tmpl, err := parseValues([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tmpl.Registry.Enabled != want {
+			t.Errorf("the registry of %q is enabled: %v, want %v", data, tmpl.Registry.Enabled, want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+/*
+Prompts for a secret value without echoing it and asks for it
+a second time to confirm it. The value is not trimmed or lower
+cased so passwords with spaces are kept as typed. When stdin is
+not a terminal, for example when the input is piped, the value
+is read from a single line without confirmation. Passwords
+are not kept in a recorded session unless they reference a Secret.
+*/
+func readSecret(prompt string) string {
+	if answer, ok := replayAnswer(prompt, true); ok {
+		fmt.Print((colorWhite), prompt)
+		fmt.Println(maskedValue)
+		return answer
+	}
+	value := readPassword(prompt)
+	recordAnswer(prompt, value, true)
+	return value
+}
+
+// Reads the password from the terminal, or from stdin when it is not a terminal
+func readPassword(prompt string) string {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		fmt.Print((colorWhite), prompt)
+		input := readInput()
+		fmt.Println()
+		return strings.TrimRight(input, "\r\n")
+	}
+
+	for {
+		fmt.Print((colorWhite), prompt)
+		first, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			inputEnded(err)
+		}
+		if len(first) == 0 {
+			return ""
+		}
+		fmt.Print((colorWhite), "Confirm the value: ")
+		second, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			inputEnded(err)
+		}
+		if string(first) == string(second) {
+			return string(first)
+		}
+		fmt.Println((colorYellow), "The values do not match, please try again")
+	}
+}
@@ -0,0 +1,236 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Set by the flags of the values command
+var (
+	profileName string
+	profileDir  string
+)
+
+// A named set of values for a common deployment topology
+type profile struct {
+	name        string
+	description string
+	apply       func(t *Template) error
+	// Optional, the values the profile needs but can not preset
+	requires []profileValue
+}
+
+// A value which must be given along with a profile
+type profileValue struct {
+	// The flag setting the value without prompts
+	flag   string
+	prompt string
+	// Returns true when the value is not set
+	missing func(t Template) bool
+	set     func(t *Template, value string)
+}
+
+// The profiles which ship with the cli
+var builtinProfiles = []profile{
+	{name: "aws-eks", description: "Istio ingress and gp2 volumes on Amazon EKS",
+		apply: func(t *Template) error {
+			t.Network.Ingress.Type = "istio"
+			t.Network.Istio.Enabled = true
+			t.Gpu.HabanaEnable = false
+			t.Monitoring.HabanaExportEnable = false
+			t.Dbs.EsStorageClass = "gp2"
+			t.Dbs.MinioStorageClass = "gp2"
+			t.Dbs.PgStorageClass = "gp2"
+			t.Dbs.RedisStorageClass = "gp2"
+			t.Monitoring.PrometheusStorageClass = "gp2"
+			t.Logging.ElastaStorageClass = "gp2"
+			return nil
+		}},
+	{name: "on-prem-nfs", description: "NFS storage and NodePort ingress without a cloud load balancer",
+		apply: func(t *Template) error {
+			t.Storage.Nfs.Enabled = true
+			t.Storage.Nfs.DefaultSc = true
+			t.Storage.Nfs.ReclaimPolicy = "Retain"
+			t.Network.Ingress.Type = "nodeport"
+			t.Network.Istio.Enabled = false
+			return nil
+		},
+		requires: []profileValue{
+			{flag: "nfs-server", prompt: "Input the IP address of the NFS server: ",
+				missing: func(t Template) bool { return t.Storage.Nfs.Server == "" },
+				set:     func(t *Template, value string) { t.Storage.Nfs.Server = value }},
+			{flag: "nfs-path", prompt: "Input the NFS export path: ",
+				missing: func(t Template) bool { return t.Storage.Nfs.Path == "" },
+				set:     func(t *Template, value string) { t.Storage.Nfs.Path = value }},
+		}},
+	{name: "air-gapped", description: "images pulled from a private registry and no external services",
+		apply: func(t *Template) error {
+			t.Registry.Enabled = true
+			t.ControlPlane.BaseConfigIntercom = false
+			return nil
+		},
+		requires: []profileValue{
+			{flag: "registry-url", prompt: "Input the URL of the registry the images are pulled from: ",
+				missing: func(t Template) bool { return t.Registry.Url == "" },
+				set:     func(t *Template, value string) { t.Registry.Url = value }},
+		}},
+	{name: "minimal-no-monitoring", description: "no monitoring, logging or Habana components",
+		apply: func(t *Template) error {
+			t.Monitoring = Monitoring{}
+			t.Logging = Logging{}
+			t.Gpu.HabanaEnable = false
+			return nil
+		}},
+	{name: "openshift", description: "OpenShift routes instead of Istio",
+		apply: func(t *Template) error {
+			t.Network.Ingress.Type = "openshift"
+			t.Network.Ingress.IstioGwEnabled = false
+			t.Network.Istio.Enabled = false
+			return nil
+		}},
+}
+
+// Returns the directory user profiles are loaded from by default
+func defaultProfileDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cnvrg-deploy-cli", "profiles")
+}
+
+// Returns the built-in profiles followed by the user profiles in the directory.
+// A user profile is an answers file, its name is the name of the file.
+func listProfiles(dir string) ([]profile, error) {
+	profiles := append([]profile{}, builtinProfiles...)
+	if dir == "" {
+		return profiles, nil
+	}
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, f := range files {
+		ext := filepath.Ext(f.Name())
+		if !f.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		profiles = append(profiles, profile{
+			name:        strings.TrimSuffix(name, filepath.Ext(name)),
+			description: "user profile from " + path,
+			apply:       func(t *Template) error { return loadAnswers(path, t) },
+		})
+	}
+	return profiles, nil
+}
+
+// Finds a profile by name, user profiles take precedence over the built-in ones
+func findProfile(name string, dir string) (profile, error) {
+	profiles, err := listProfiles(dir)
+	if err != nil {
+		return profile{}, err
+	}
+	for i := len(profiles) - 1; i >= 0; i-- {
+		if profiles[i].name == name {
+			return profiles[i], nil
+		}
+	}
+	var names []string
+	for _, p := range profiles {
+		names = append(names, p.name)
+	}
+	return profile{}, fmt.Errorf("unknown profile %v, the available profiles are: %v", name, strings.Join(names, ", "))
+}
+
+// Applies the named profile on top of the Template
+func applyProfile(name string, t *Template) error {
+	p, err := findProfile(name, profileDir)
+	if err != nil {
+		return err
+	}
+	InfoLogger.Printf("Applying the %v profile\n", p.name)
+	return p.apply(t)
+}
+
+// Returns an error naming the flag of every value the profile needs which is not set
+func checkProfile(name string, t Template) error {
+	p, err := findProfile(name, profileDir)
+	if err != nil {
+		return err
+	}
+	var flags []string
+	for _, v := range p.requires {
+		if v.missing(t) {
+			flags = append(flags, "--"+v.flag)
+		}
+	}
+	if len(flags) > 0 {
+		return fmt.Errorf("the %v profile needs %v to generate the values without prompts", p.name, strings.Join(flags, ", "))
+	}
+	return nil
+}
+
+// Prompts for the values the profile needs which are not set
+func promptProfileValues(p profile, t *Template) {
+	for _, v := range p.requires {
+		if v.missing(*t) {
+			v.set(t, promptFor(v.prompt, checkNotEmpty))
+		}
+	}
+}
+
+// Prompts for the number of a profile, no input is no profile. The answer
+// is recorded and replayed with the title of the menu like promptMenu.
+func selectProfile(max int) string {
+	currentMenu = "Deployment Profiles"
+	defer func() { currentMenu = "" }()
+	return promptFor("Please make your selection: ", optional(checkInt(1, max)))
+}
+
+// Lets the user pick a profile from the Quick Start menu and applies it
+func gatherProfile() {
+	profiles, err := listProfiles(profileDir)
+	if err != nil {
+		ErrorLogger.Println(err)
+		fmt.Println((colorYellow), err)
+		return
+	}
+	fmt.Println()
+	fmt.Println((colorGreen), "----Deployment Profiles----")
+	fmt.Println((colorGreen), "Select a profile to preset the values for a common topology")
+	for i, p := range profiles {
+		fmt.Printf("%v Press '%d' for %v: %v\n", colorBlue, i+1, p.name, p.description)
+	}
+	fmt.Println((colorBlue), "Press 'return' to continue without a profile")
+	input := selectProfile(len(profiles))
+	if input == "" {
+		return
+	}
+	i, _ := strconv.Atoi(input)
+	t := currentTemplate()
+	if err := profiles[i-1].apply(&t); err != nil {
+		ErrorLogger.Println(err)
+		fmt.Println((colorYellow), err)
+		return
+	}
+	promptProfileValues(profiles[i-1], &t)
+	applyTemplate(t)
+	fmt.Printf("%v Applied the %v profile\n", colorGreen, profiles[i-1].name)
+}
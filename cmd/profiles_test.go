@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// Every built-in profile is valid with the cluster domain and the values it requires
+func TestBuiltinProfilesValidate(t *testing.T) {
+	values := map[string]string{"registry-url": "registry.example.com", "nfs-server": "10.0.0.5", "nfs-path": "/exports/cnvrg"}
+	for _, p := range builtinProfiles {
+		tmpl := defaultTemplate()
+		tmpl.ClusterDomain.ClusterDomain = "example.com"
+		if err := p.apply(&tmpl); err != nil {
+			t.Fatalf("%v: %v", p.name, err)
+		}
+		for _, v := range p.requires {
+			v.set(&tmpl, values[v.flag])
+		}
+		if err := checkProfile(p.name, tmpl); err != nil {
+			t.Errorf("%v: %v", p.name, err)
+		}
+		for _, err := range validateTemplate(tmpl) {
+			t.Errorf("%v: %v %v", p.name, err.Field, err.Message)
+		}
+	}
+}
+
+// The air-gapped profile names the flag of the registry URL when it is not set
+func TestAirGappedProfileNeedsRegistry(t *testing.T) {
+	tmpl := defaultTemplate()
+	tmpl.ClusterDomain.ClusterDomain = "example.com"
+	if err := applyProfile("air-gapped", &tmpl); err != nil {
+		t.Fatal(err)
+	}
+	err := checkProfile("air-gapped", tmpl)
+	if err == nil || !strings.Contains(err.Error(), "--registry-url") {
+		t.Errorf("the missing registry URL was not reported with its flag, got %v", err)
+	}
+}
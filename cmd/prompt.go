@@ -0,0 +1,303 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Matches a Kubernetes quantity, like 100Gi or 1.5T
+var quantityPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(Ki|Mi|Gi|Ti|Pi|Ei|k|M|G|T|P|E)?$`)
+
+// Matches a host name, like nfs.example.com
+var hostPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*$`)
+
+// Every prompt reads from the same reader so no piped input is lost between prompts
+var stdin = bufio.NewReader(os.Stdin)
+
+/*
+Shows the prompt and reads a line of input with the surrounding spaces
+removed, the case of the input is kept. The answer is taken from the
+replayed session when there is one and is added to the recorded session.
+The cli exits when the input ends, otherwise the menus would prompt
+forever on a closed stdin.
+*/
+func readLine(prompt string) string {
+	fmt.Print((colorWhite), prompt)
+	if answer, ok := replayAnswer(prompt, false); ok {
+		fmt.Println(answer)
+		return answer
+	}
+	input := strings.TrimSpace(readInput())
+	recordAnswer(prompt, input, false)
+	return input
+}
+
+// Reads a line from stdin, the cli exits when the input ended or can not be read
+func readInput() string {
+	input, err := stdin.ReadString('\n')
+	if err != nil && input == "" {
+		inputEnded(err)
+	}
+	return input
+}
+
+// Exits the cli, used when no more input can be read for a prompt
+func inputEnded(err error) {
+	fmt.Println()
+	if err != io.EOF {
+		ErrorLogger.Println(err)
+	}
+	ErrorLogger.Println("The input ended before the values were saved")
+	fmt.Println((colorYellow), "The input ended before the values were saved")
+	os.Exit(1)
+}
+
+// Prompts until the check accepts the input, a nil check accepts any input
+func promptFor(label string, check func(string) error) string {
+	for {
+		input := readLine(label)
+		if check == nil {
+			return input
+		}
+		if err := check(input); err != nil {
+			fmt.Println((colorYellow), err)
+			continue
+		}
+		return input
+	}
+}
+
+// Prompts for a value which is kept as typed, like an image tag or a client ID
+func promptString(label string) string {
+	return promptFor(label, nil)
+}
+
+// Prompts for a value which is not case sensitive, like a domain, it is returned in lower case
+func promptLower(label string) string {
+	return strings.ToLower(promptFor(label, nil))
+}
+
+// Prompts for a number between min and max
+func promptInt(label string, min int, max int) int {
+	n, _ := strconv.Atoi(promptFor(label, checkInt(min, max)))
+	return n
+}
+
+// Prompts for the selection of a menu with the options 1 to max, the
+// answer is recorded and replayed with the title of the menu
+func promptMenu(menu string, max int) int {
+	currentMenu = menu
+	defer func() { currentMenu = "" }()
+	return promptInt("Please make your selection: ", 1, max)
+}
+
+// Prompts for a yes or no answer
+func promptBool(label string) bool {
+	answer := promptFor(label, func(s string) error {
+		if _, ok := parseYesNo(s); !ok {
+			return fmt.Errorf("Please answer yes or no")
+		}
+		return nil
+	})
+	value, _ := parseYesNo(answer)
+	return value
+}
+
+// Prompts for a yes or no answer, no input is a no
+func promptConfirm(label string) bool {
+	answer := promptFor(label, func(s string) error {
+		if _, ok := parseYesNo(s); !ok && s != "" {
+			return fmt.Errorf("Please answer yes or no")
+		}
+		return nil
+	})
+	value, _ := parseYesNo(answer)
+	return value
+}
+
+// Prompts for one of the options, the case of the input is ignored
+func promptEnum(label string, options ...string) string {
+	answer := promptFor(label, func(s string) error {
+		if _, ok := matchOption(s, options); !ok {
+			return fmt.Errorf("Please enter one of %v", strings.Join(options, ", "))
+		}
+		return nil
+	})
+	option, _ := matchOption(answer, options)
+	return option
+}
+
+/*
+Prompts for a Kubernetes quantity, a bare number is taken as Gi to
+keep the storage sizes compatible with the earlier prompts. No input
+returns an empty string so the current value can be kept.
+*/
+func promptQuantity(label string) string {
+	size := promptFor(label, optional(checkQuantity))
+	if _, err := strconv.Atoi(size); err == nil {
+		size += "Gi"
+	}
+	return size
+}
+
+// Prompts for a duration like 24h or 90m, no input returns an empty string
+func promptDuration(label string) string {
+	return promptFor(label, optional(checkDuration))
+}
+
+// Prompts for an http or https URL, no input returns an empty string
+func promptURL(label string) string {
+	return promptFor(label, optional(checkURL))
+}
+
+// Prompts for an IP address or a host name, no input returns an empty string
+func promptHost(label string) string {
+	return strings.ToLower(promptFor(label, optional(checkHost)))
+}
+
+// Prompts for key value pairs, one per line, until no input is given
+func promptMap(label string) map[string]string {
+	values := map[string]string{}
+	for {
+		text := promptFor(label, func(s string) error {
+			if key, _, found := strings.Cut(s, ":"); s != "" && (!found || strings.TrimSpace(key) == "") {
+				return fmt.Errorf("Please use the format [key: value]")
+			}
+			return nil
+		})
+		if text == "" {
+			return values
+		}
+		key, value, _ := strings.Cut(text, ":")
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+}
+
+// Prompts for a list, one value per line, until no input is given. A nil check accepts any value
+func promptList(label string, check func(string) error) []string {
+	fmt.Println((colorBlue), label)
+	fmt.Println((colorWhite), "Enter 1 value per line. Press 'return' when done: ")
+	var list []string
+	for {
+		value := promptFor("", optional(check))
+		if value == "" {
+			return list
+		}
+		list = append(list, value)
+	}
+}
+
+// Wraps a check so no input is also accepted
+func optional(check func(string) error) func(string) error {
+	return func(s string) error {
+		if s == "" || check == nil {
+			return nil
+		}
+		return check(s)
+	}
+}
+
+// Returns a check for a number between min and max
+func checkInt(min int, max int) func(string) error {
+	return func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < min || n > max {
+			return fmt.Errorf("Please enter a number between %d and %d", min, max)
+		}
+		return nil
+	}
+}
+
+func checkNotEmpty(s string) error {
+	if s == "" {
+		return fmt.Errorf("Please enter a value")
+	}
+	return nil
+}
+
+func checkQuantity(s string) error {
+	if !quantityPattern.MatchString(s) {
+		return fmt.Errorf("Please enter a size like 100Gi or 500Mi")
+	}
+	return nil
+}
+
+func checkDuration(s string) error {
+	if _, err := time.ParseDuration(s); err != nil {
+		return fmt.Errorf("Please enter a duration like 24h or 90m")
+	}
+	return nil
+}
+
+func checkURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("Please enter a URL like https://example.com")
+	}
+	return nil
+}
+
+func checkIP(s string) error {
+	if net.ParseIP(s) == nil {
+		return fmt.Errorf("Please enter an IP address like 10.0.0.5")
+	}
+	return nil
+}
+
+func checkCIDR(s string) error {
+	if _, _, err := net.ParseCIDR(s); err != nil {
+		return fmt.Errorf("Please enter an IP range like 10.0.0.0/16")
+	}
+	return nil
+}
+
+func checkHost(s string) error {
+	if net.ParseIP(s) == nil && !hostPattern.MatchString(s) {
+		return fmt.Errorf("Please enter an IP address or a host name")
+	}
+	return nil
+}
+
+func checkPort(s string) error {
+	return checkInt(1, 65535)(s)
+}
+
+func checkEmail(s string) error {
+	if !validEmail(s) {
+		return fmt.Errorf("Please enter an email address, like cnvrg@example.com")
+	}
+	return nil
+}
+
+// Returns the answer of a yes or no question and false when it is neither
+func parseYesNo(s string) (bool, bool) {
+	switch strings.ToLower(s) {
+	case "y", "yes", "true":
+		return true, true
+	case "n", "no", "false":
+		return false, true
+	}
+	return false, false
+}
+
+// Returns the option which matches the input without regard to case
+func matchOption(s string, options []string) (string, bool) {
+	for _, o := range options {
+		if strings.EqualFold(s, o) {
+			return o, true
+		}
+	}
+	return "", false
+}
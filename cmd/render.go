@@ -0,0 +1,488 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The values.tmpl shipped with the cli
+//
+//go:embed values.tmpl
+var embeddedTemplate string
+
+// Set by the --template flag of the values command
+var templateFile string
+
+// Functions available to values.tmpl
+var templateFuncs = template.FuncMap{
+	"toYaml": toYaml,
+	"values": valuesYaml,
+}
+
+// Encodes the values with the layout of the cnvrg chart, without the
+// document start so other content can be placed around them
+func valuesYaml(t *Template) (string, error) {
+	data, err := encodeValues(buildValues(t))
+	return strings.TrimPrefix(string(data), "---\n"), err
+}
+
+// Encodes a value as a single line of YAML so it can be placed after a key
+// in the template. Strings are quoted when needed, lists and maps are written
+// in flow style and multi-line strings are double quoted.
+func toYaml(v interface{}) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return "", err
+	}
+	flowStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	if err := encoder.Encode(&node); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Sets every node to the flow style so the value fits on a single line
+func flowStyle(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		node.Style = yaml.FlowStyle
+	case yaml.ScalarNode:
+		if strings.Contains(node.Value, "\n") {
+			node.Style = yaml.DoubleQuotedStyle
+		}
+	}
+	for _, child := range node.Content {
+		flowStyle(child)
+	}
+}
+
+// Parses the embedded values.tmpl, or the custom template when a file is given
+func parseValuesTemplate(file string) (*template.Template, error) {
+	if file == "" {
+		return template.New("values.tmpl").Funcs(templateFuncs).Parse(embeddedTemplate)
+	}
+	InfoLogger.Printf("Using the custom template %v\n", file)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the template: %w", err)
+	}
+	t, err := template.New(filepath.Base(file)).Funcs(templateFuncs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the template %v: %w", file, err)
+	}
+	return t, nil
+}
+
+/*
+Renders the Template to the values file with values.tmpl, or the custom
+template given with --template. The embedded template writes the values
+built by buildValues, only the values which differ from the defaults of
+the chart. Unless the secrets mode is inline, the secret values are left
+out or replaced with references to their Secret before rendering, so a
+custom template can not write them either. The output is checked to be
+valid YAML.
+*/
+func renderValues(t *Template) ([]byte, error) {
+	values := valuesSecrets(*t)
+	t = &values
+	var buf bytes.Buffer
+	if err := temp.Execute(&buf, t); err != nil {
+		return nil, fmt.Errorf("unable to render the values: %w", err)
+	}
+	var out map[string]interface{}
+	if err := yaml.Unmarshal(buf.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("the rendered values are not valid YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Encodes the mapping as a YAML document, an empty mapping is an empty document
+func encodeValues(m *valuesMapping) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	if m.empty() {
+		return buf.Bytes(), nil
+	}
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m); err != nil {
+		return nil, fmt.Errorf("unable to render the values: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("unable to render the values: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// A mapping of the values file which keeps its keys in the order they were set
+type valuesMapping struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newValuesMapping() *valuesMapping {
+	return &valuesMapping{values: map[string]interface{}{}}
+}
+
+// Sets the key to the value
+func (m *valuesMapping) set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Sets the key when the value is not empty, like the if action of a template
+func (m *valuesMapping) setNotEmpty(key string, value interface{}) {
+	if truth, _ := template.IsTrue(value); truth {
+		m.set(key, value)
+	}
+}
+
+// Returns the mapping under the key, it is added when missing
+func (m *valuesMapping) child(key string) *valuesMapping {
+	if c, ok := m.values[key].(*valuesMapping); ok {
+		return c
+	}
+	c := newValuesMapping()
+	m.set(key, c)
+	return c
+}
+
+// Returns the value at the path of keys
+func (m *valuesMapping) lookup(path []string) (interface{}, bool) {
+	value, ok := m.values[path[0]]
+	if !ok || len(path) == 1 {
+		return value, ok
+	}
+	c, ok := value.(*valuesMapping)
+	if !ok {
+		return nil, false
+	}
+	return c.lookup(path[1:])
+}
+
+// Sets the value at the path of keys, the mappings on the way are added when missing
+func (m *valuesMapping) setPath(path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		m = m.child(key)
+	}
+	m.set(path[len(path)-1], value)
+}
+
+// Returns true when the mapping holds no values, empty child mappings are not written
+func (m *valuesMapping) empty() bool {
+	for _, v := range m.values {
+		if c, ok := v.(*valuesMapping); !ok || !c.empty() {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Adds the mapping of a component under the key. The component is only
+written when it is not enabled by default or when any of the values set
+by the function differ from the defaults, the enabled key comes first.
+*/
+func (m *valuesMapping) component(key string, enabled bool, def bool, values func(c *valuesMapping)) {
+	rest := newValuesMapping()
+	values(rest)
+	if enabled == def && rest.empty() {
+		return
+	}
+	c := m.child(key)
+	c.set("enabled", enabled)
+	for _, k := range rest.keys {
+		c.set(k, rest.values[k])
+	}
+}
+
+// Writes the keys in order and leaves the empty child mappings out
+func (m *valuesMapping) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range m.keys {
+		value := m.values[key]
+		if c, ok := value.(*valuesMapping); ok && c.empty() {
+			continue
+		}
+		var v yaml.Node
+		if err := v.Encode(value); err != nil {
+			return nil, err
+		}
+		if _, ok := value.(*valuesMapping); !ok {
+			flowStyle(&v)
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &v)
+	}
+	return node, nil
+}
+
+// Sets the secret value, or the reference to its Secret when the value references one
+func setSecretValue(m *valuesMapping, key string, refKey string, value string) {
+	if ref, ok := parseSecretRef(value); ok {
+		m.set(refKey, ref)
+		return
+	}
+	m.setNotEmpty(key, value)
+}
+
+// Sets nothing, for the components which only have the enabled key
+func noValues(*valuesMapping) {}
+
+/*
+Builds the values file from the Template. A section is only written
+when one of its values differs from the default of the chart, and the
+enabled key of a component is written along with any of its values.
+*/
+func buildValues(t *Template) *valuesMapping {
+	m := newValuesMapping()
+	m.setNotEmpty("clusterDomain", t.ClusterDomain.ClusterDomain)
+	if t.ClusterInteralDomain.Domain != "cluster.local" {
+		m.set("clusterInternalDomain", t.ClusterInteralDomain.Domain)
+	}
+	m.setNotEmpty("imageHub", t.ClusterDomain.ImageHub)
+	m.setNotEmpty("labels", map[string]string(t.Labels))
+	m.setNotEmpty("annotations", map[string]string(t.Annotations))
+
+	n := t.Network
+	networking := m.child("networking")
+	networking.component("https", n.Https.Enabled, false, func(c *valuesMapping) {
+		c.setNotEmpty("certSecret", n.Https.CertSecret)
+	})
+	networking.component("proxy", n.Proxy.Enabled, false, func(c *valuesMapping) {
+		c.setNotEmpty("httpProxy", n.Proxy.HttpProxy)
+		c.setNotEmpty("httpsProxy", n.Proxy.HttpsProxy)
+		c.setNotEmpty("noProxy", n.Proxy.NoProxy)
+	})
+	ingress := networking.child("ingress")
+	ingress.setNotEmpty("type", n.Ingress.Type)
+	if !n.Ingress.IstioGwEnabled {
+		ingress.set("istioGwEnabled", false)
+	}
+	ingress.setNotEmpty("istioGwName", n.Ingress.IstioGwName)
+	ingress.setNotEmpty("external", n.Ingress.External)
+	networking.component("istio", n.Istio.Enabled, true, func(c *valuesMapping) {
+		c.setNotEmpty("externalIp", n.Istio.ExternalIp)
+		c.setNotEmpty("ingressSvcAnnotations", n.Istio.IngressSvcAnnotations)
+		c.setNotEmpty("ingressSvcExtraPorts", n.Istio.IngressSvcExtraPorts)
+		c.setNotEmpty("lbSourceRanges", n.Istio.LbSourceRanges)
+	})
+
+	l := t.Logging
+	logging := m.child("logging")
+	logging.component("fluentbit", l.FluentbitEnable, true, noValues)
+	logging.component("elastalert", l.ElastalertEnable, true, func(c *valuesMapping) {
+		c.setNotEmpty("storageSize", l.ElastaStorageSize)
+		c.setNotEmpty("storageClass", l.ElastaStorageClass)
+		c.setNotEmpty("nodeSelector", l.ElastaNodeSelector)
+	})
+	logging.component("kibana", l.KibanaEnable, true, func(c *valuesMapping) {
+		c.setNotEmpty("svcName", l.KibanaSvcName)
+	})
+
+	// The values of the optional sections are only written when they are enabled
+	if t.Registry.Enabled {
+		registry := m.child("registry")
+		registry.setNotEmpty("url", t.Registry.Url)
+		registry.setNotEmpty("user", t.Registry.User)
+		setSecretValue(registry, "password", "passwordSecretRef", t.Registry.Password)
+	}
+	if t.Tenancy.Enabled {
+		tenancy := m.child("tenancy")
+		tenancy.set("enabled", true)
+		tenancy.set("key", t.Tenancy.Key)
+		tenancy.set("value", t.Tenancy.Value)
+	}
+	if t.Sso.Enabled {
+		sso := m.child("sso")
+		sso.set("enabled", true)
+		sso.setNotEmpty("adminUser", t.Sso.AdminUser)
+		sso.setNotEmpty("provider", t.Sso.Provider)
+		sso.setNotEmpty("emailDomain", t.Sso.EmailDomain)
+		sso.setNotEmpty("clientId", t.Sso.ClientId)
+		setSecretValue(sso, "clientSecret", "clientSecretRef", t.Sso.ClientSecret)
+		sso.setNotEmpty("azureTenant", t.Sso.AzureTenant)
+		sso.setNotEmpty("oidcIssuerUrl", t.Sso.OidcIssuerUrl)
+	}
+
+	storage := m.child("storage")
+	if nfs := t.Storage.Nfs; nfs.Enabled {
+		s := storage.child("nfs")
+		s.set("enabled", true)
+		s.setNotEmpty("server", nfs.Server)
+		s.setNotEmpty("path", nfs.Path)
+		s.setNotEmpty("defaultSc", nfs.DefaultSc)
+		s.setNotEmpty("reclaimPolicy", nfs.ReclaimPolicy)
+		s.setNotEmpty("image", nfs.Image)
+	}
+	if hostpath := t.Storage.Hostpath; hostpath.Enabled {
+		s := storage.child("hostpath")
+		s.set("enabled", true)
+		s.setNotEmpty("defaultSc", hostpath.DefaultSc)
+		if hostpath.Path != "/cnvrg-hostpath-storage" {
+			s.set("path", hostpath.Path)
+		}
+		s.setNotEmpty("reclaimPolicy", hostpath.ReclaimPolicy)
+		s.setNotEmpty("nodeSelector", hostpath.NodeSelector)
+	}
+
+	gpu := m.child("gpu")
+	gpu.component("nvidiaDp", t.Gpu.NvidiaEnable, true, noValues)
+	gpu.component("habanaDp", t.Gpu.HabanaEnable, true, noValues)
+	m.component("configReloader", t.ConfigReloader.Enabled, true, noValues)
+	m.component("capsule", t.Capsule.Enabled, true, func(c *valuesMapping) {
+		c.setNotEmpty("image", t.Capsule.Image)
+	})
+	m.component("backup", t.Backup.Enabled, true, func(c *valuesMapping) {
+		c.setNotEmpty("rotation", t.Backup.Rotation)
+		c.setNotEmpty("period", t.Backup.Period)
+	})
+
+	buildMonitoring(m.child("monitoring"), t.Monitoring)
+	buildDbs(m.child("dbs"), t.Dbs)
+	buildControlPlane(m.child("controlPlane"), t.ControlPlane)
+	return m
+}
+
+// Builds the monitoring section of the values file
+func buildMonitoring(m *valuesMapping, mon Monitoring) {
+	m.component("dcgmExporter", mon.DcgmExportEnable, true, noValues)
+	m.component("habanaExporter", mon.HabanaExportEnable, true, noValues)
+	m.component("nodeExporter", mon.NodeExportEnable, true, noValues)
+	m.component("kubeStateMetrics", mon.KubeStateMetricEnable, true, noValues)
+	m.component("grafana", mon.GrafanaEnable, true, func(c *valuesMapping) {
+		c.setNotEmpty("svcName", mon.GrafanaSvcName)
+	})
+	m.component("prometheusOperator", mon.PrometheusOperatorEnable, true, noValues)
+	m.component("prometheus", mon.PrometheusEnable, true, func(c *valuesMapping) {
+		c.setNotEmpty("storageSize", mon.PrometheusStorageSize)
+		c.setNotEmpty("storageClass", mon.PrometheusStorageClass)
+		c.setNotEmpty("nodeSelector", mon.PrometheusNodeSelector)
+	})
+	m.component("defaultServiceMonitors", mon.DefaultSvcMonitorsEnable, true, noValues)
+	m.component("cnvrgIdleMetricsExporter", mon.CnvrgIdleMetricsEnable, true, func(c *valuesMapping) {
+		c.setNotEmpty("labels", mon.CnvrgIdleMetricsLabels)
+	})
+}
+
+// Builds the databases section of the values file
+func buildDbs(m *valuesMapping, d Dbs) {
+	m.component("cvat", d.CvatEnable, false, noValues)
+	m.component("es", d.EsEnable, true, func(c *valuesMapping) {
+		c.setNotEmpty("storageSize", d.EsStorageSize)
+		c.setNotEmpty("storageClass", d.EsStorageClass)
+		c.setNotEmpty("patchEsNodes", d.EsPatchNodes)
+		c.setNotEmpty("nodeSelector", d.EsNodeSelector)
+		cleanup := c.child("cleanupPolicy")
+		cleanup.setNotEmpty("all", d.CleanUpAll)
+		cleanup.setNotEmpty("app", d.CleanUpApp)
+		cleanup.setNotEmpty("jobs", d.CleanUpJobs)
+		cleanup.setNotEmpty("endpoints", d.CleanUpEndpoints)
+	})
+	m.component("minio", d.MinioEnable, true, func(c *valuesMapping) {
+		c.setNotEmpty("storageSize", d.MinioStorageSize)
+		c.setNotEmpty("storageClass", d.MinioStorageClass)
+		c.setNotEmpty("nodeSelector", d.MinioNodeSelector)
+	})
+	m.component("pg", d.PgEnable, true, func(c *valuesMapping) {
+		c.setNotEmpty("storageSize", d.PgStorageSize)
+		c.setNotEmpty("storageClass", d.PgStorageClass)
+		c.setNotEmpty("nodeSelector", d.PgNodeSelector)
+		hugePages := c.child("hugePages")
+		hugePages.setNotEmpty("enabled", d.PgPagesEnable)
+		hugePages.setNotEmpty("size", d.PgPagesSize)
+		hugePages.setNotEmpty("memory", d.PgPagesMemory)
+	})
+	m.component("redis", d.RedisEnable, true, func(c *valuesMapping) {
+		c.setNotEmpty("storageSize", d.RedisStorageSize)
+		c.setNotEmpty("storageClass", d.RedisStorageClass)
+		c.setNotEmpty("nodeSelector", d.RedisNodeSelector)
+	})
+}
+
+// Builds the control plane section of the values file
+func buildControlPlane(m *valuesMapping, cp ControlPlane) {
+	m.setNotEmpty("image", cp.Image)
+	baseConfig := m.child("baseConfig")
+	baseConfig.setNotEmpty("agentCustomTag", cp.BaseConfigAgentTag)
+	baseConfig.setNotEmpty("intercom", cp.BaseConfigIntercom)
+	baseConfig.setNotEmpty("featureFlags", cp.BaseConfigFeatureFlags)
+	baseConfig.setNotEmpty("cnvrgPrivilegedJob", cp.BaseConfigCnvrgPrivileged)
+	m.component("hyper", cp.HyperEnable, true, noValues)
+	m.component("cnvrgScheduler", cp.CnvrgScheduleEnable, true, noValues)
+	m.component("cnvrgClusterProvisionerOperator", cp.CnvrgClusterProvisionerEnable, false, noValues)
+
+	if cp.ObjectStorageType != "" {
+		storage := m.child("objectStorage")
+		storage.set("type", cp.ObjectStorageType)
+		storage.setNotEmpty("bucket", cp.ObjectStorageBucket)
+		storage.setNotEmpty("region", cp.ObjectStorageRegion)
+		storage.setNotEmpty("accessKey", cp.ObjectStorageAccessKey)
+		setSecretValue(storage, "secretKey", "secretKeyRef", cp.ObjectStorageSecretKey)
+		storage.setNotEmpty("endpoint", cp.ObjectStorageEndpoint)
+		storage.setNotEmpty("azureAccountName", cp.ObjectStorageAzureAcountName)
+		storage.setNotEmpty("azureContainer", cp.ObjectStorageAzureContainer)
+		storage.setNotEmpty("gcpSecretRef", cp.ObjectStorageGcpSecretRef)
+		storage.setNotEmpty("gcpProject", cp.ObjectStorageGcpProject)
+	}
+
+	// The horizontal pod autoscaler of a control plane service
+	hpa := func(enabled bool, maxReplicas int) func(c *valuesMapping) {
+		return func(c *valuesMapping) {
+			c.component("hpa", enabled, true, func(h *valuesMapping) {
+				h.setNotEmpty("maxReplicas", maxReplicas)
+			})
+		}
+	}
+	m.component("searchkiq", cp.SearchkiqEnable, true, hpa(cp.SearchkiqHpaEnable, cp.SearchkiqHpaMaxReplicas))
+	m.component("sidekiq", cp.SidekiqEnable, true, func(c *valuesMapping) {
+		c.setNotEmpty("split", cp.SidekiqSplit)
+		hpa(cp.SidekiqHpaEnable, cp.SidekiqHpaMaxReplicas)(c)
+	})
+	m.component("cnvrgRouter", cp.CnvrgRouterEnable, false, func(c *valuesMapping) {
+		c.setNotEmpty("image", cp.CnvrgRouterImage)
+	})
+
+	smtp := m.child("smtp")
+	smtp.setNotEmpty("server", cp.SmtpServer)
+	smtp.setNotEmpty("port", cp.SmtpPort)
+	smtp.setNotEmpty("username", cp.SmtpUsername)
+	setSecretValue(smtp, "password", "passwordSecretRef", cp.SmtpPassword)
+	smtp.setNotEmpty("domain", cp.SmtpDomain)
+	smtp.setNotEmpty("opensslVerifyMode", cp.SmtpOpenSslMode)
+	smtp.setNotEmpty("sender", cp.SmtpSender)
+
+	m.component("systemkiq", cp.SystemkiqEnable, true, hpa(cp.SystemkiqHpaEnable, cp.SystemkiqHpaMaxReplicas))
+	m.component("webapp", cp.WebappEnable, true, func(c *valuesMapping) {
+		c.setNotEmpty("svcName", cp.WebappSvcName)
+		c.setNotEmpty("replicas", cp.WebappReplicas)
+		hpa(cp.WebappHpaEnable, cp.WebappHpaMaxReplicas)(c)
+	})
+	m.component("mpi", cp.MpiEnable, true, func(c *valuesMapping) {
+		c.setNotEmpty("image", cp.MpiImage)
+		c.setNotEmpty("kubectlDeliveryImage", cp.MpiKubectlImage)
+		c.setNotEmpty("extraArgs", cp.MpiExtraArgs)
+		registry := c.child("registry")
+		registry.setNotEmpty("url", cp.MpiRegistryUrl)
+		registry.setNotEmpty("user", cp.MpiRegistryUser)
+		setSecretValue(registry, "password", "passwordSecretRef", cp.MpiRegistryPassword)
+	})
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+)
+
+// The embedded values.tmpl writes the values of buildValues and a custom
+// template given with --template replaces it
+func TestRenderWithTemplate(t *testing.T) {
+	defer func(t *template.Template) { temp = t }(temp)
+	tmpl := defaultTemplate()
+	tmpl.ClusterDomain.ClusterDomain = "example.com"
+
+	data, err := renderValues(&tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "---\nclusterDomain: example.com\n"; string(data) != want {
+		t.Errorf("the embedded template rendered %q, want %q", data, want)
+	}
+
+	file := filepath.Join(t.TempDir(), "custom.tmpl")
+	if err := os.WriteFile(file, []byte("domain: {{ toYaml .ClusterDomain.ClusterDomain }}\n{{ values . }}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if temp, err = parseValuesTemplate(file); err != nil {
+		t.Fatal(err)
+	}
+	data, err = renderValues(&tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "domain: example.com\nclusterDomain: example.com\n"; string(data) != want {
+		t.Errorf("the custom template rendered %q, want %q", data, want)
+	}
+}
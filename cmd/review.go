@@ -0,0 +1,238 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+/*
+Shows every value which differs from the defaults, the warnings and
+the problems of the values before they are written. The user can save,
+go back into a section and return to the review, or cancel without
+writing anything. The menus are done once the values are saved.
+*/
+func reviewValues() navigation {
+	InfoLogger.Println("In the reviewValues function")
+	t := currentTemplate()
+	fmt.Println()
+	fmt.Println((colorGreen), "------------------------------- Review -------------------------------")
+	printChangedValues(t)
+	if warnings := reviewWarnings(t); len(warnings) > 0 {
+		fmt.Println()
+		fmt.Printf("%v Found %d warning(s), the values can still be saved:\n", colorYellow, len(warnings))
+		for _, w := range warnings {
+			fmt.Println((colorWhite), " ", w.Error())
+		}
+	}
+	errs := validateTemplate(t)
+	if len(errs) > 0 {
+		fmt.Println()
+		printValidationErrors(errs)
+	}
+
+	fmt.Println()
+	fmt.Printf("%v Press '1' to Save and Generate the %v file\n", colorBlue, valuesFile)
+	fmt.Println((colorBlue), "Press '2' to go back into a section")
+	fmt.Println((colorBlue), "Press '3' to Cancel and return to the Main Menu")
+	fmt.Println((colorBlue), "Press '4' to Exit without writing the values")
+	intVar := promptMenu("Review Menu", 4)
+	switch intVar {
+	case 1:
+		if len(errs) > 0 {
+			fmt.Println((colorYellow), "Please fix the problems above before generating the values file")
+			return navigation{}
+		}
+		if err := saveValues(t); err != nil {
+			fmt.Println((colorYellow), err)
+			return navigation{}
+		}
+		return navigation{done: true}
+	case 2:
+		fmt.Println()
+		fmt.Println((colorGreen), "Please select the section to go back into")
+		for i, s := range menuSections {
+			fmt.Printf("%v Press '%d' for %v\n", colorBlue, i+1, s.name)
+		}
+		fmt.Printf("%v Press '%d' to return to the review\n", colorBlue, len(menuSections)+1)
+		section := promptMenu("Review Sections Menu", len(menuSections)+1)
+		if section <= len(menuSections) {
+			editSection(menuSections[section-1])
+		}
+	case 3:
+		fmt.Println((colorWhite), "Nothing was written, returning to Main Menu")
+		return navigation{back: true}
+	case 4:
+		if confirmExit() {
+			return navigation{done: true}
+		}
+	}
+	return navigation{}
+}
+
+// A value which differs from its default
+type changedValue struct {
+	label string
+	value string
+	def   string
+}
+
+// The section of the review the values of each top level struct of the Template are listed under
+var reviewSections = map[string]string{
+	"ClusterDomain":        "Cluster",
+	"ClusterInteralDomain": "Cluster",
+	"Labels":               "Cluster",
+	"Annotations":          "Cluster",
+	"Network":              "Networking",
+	"Logging":              "Logging",
+	"Registry":             "Registry",
+	"Tenancy":              "Tenancy",
+	"Sso":                  "SSO",
+	"Storage":              "Storage",
+	"ConfigReloader":       "Misc",
+	"Capsule":              "Misc",
+	"Backup":               "Misc",
+	"Gpu":                  "Misc",
+	"Monitoring":           "Monitoring",
+	"ControlPlane":         "Control Plane",
+	"Dbs":                  "Databases",
+}
+
+// Prints the values which differ from the defaults, grouped by section
+func printChangedValues(t Template) {
+	changed := changedValues(maskSecrets(t))
+	for _, section := range tuiSections {
+		if len(changed[section.name]) == 0 {
+			continue
+		}
+		fmt.Println((colorBlue), section.name)
+		for _, c := range changed[section.name] {
+			fmt.Printf("%v   %v: %v (default: %v)\n", colorWhite, c.label, c.value, c.def)
+		}
+	}
+	if len(changed) == 0 {
+		fmt.Println((colorWhite), "Every value is set to its default")
+	}
+}
+
+/*
+Compares every value of the Template with defaultTemplate and returns
+the ones which differ by the name of their section, taken from
+reviewSections. The labels of the full screen UI are used where a value
+has one, the other values are labeled with their path in the Template.
+*/
+func changedValues(t Template) map[string][]changedValue {
+	defaults := defaultTemplate()
+	tv := reflect.ValueOf(&t).Elem()
+
+	// The labels of the values in the full screen UI
+	labels := map[uintptr]string{}
+	for _, section := range tuiSections {
+		for _, f := range section.fields {
+			labels[reflect.ValueOf(f.field(&t)).Pointer()] = f.label
+		}
+	}
+
+	changed := map[string][]changedValue{}
+	for j := 0; j < tv.NumField(); j++ {
+		name := tv.Type().Field(j).Name
+		section := reviewSections[name]
+		diffTemplateValues(name, tv.Field(j), reflect.ValueOf(defaults).Field(j), func(path string, value reflect.Value, def reflect.Value) {
+			label, ok := labels[value.UnsafeAddr()]
+			if !ok {
+				label = path
+			}
+			changed[section] = append(changed[section], changedValue{label: label, value: formatValue(value), def: formatValue(def)})
+		})
+	}
+	return changed
+}
+
+// Calls add for every value in the struct which differs from the default, empty maps and lists are equal
+func diffTemplateValues(path string, value reflect.Value, def reflect.Value, add func(string, reflect.Value, reflect.Value)) {
+	switch value.Kind() {
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			diffTemplateValues(path+"."+value.Type().Field(i).Name, value.Field(i), def.Field(i), add)
+		}
+		return
+	case reflect.Map, reflect.Slice:
+		if value.Len() == 0 && def.Len() == 0 {
+			return
+		}
+	}
+	if !reflect.DeepEqual(value.Interface(), def.Interface()) {
+		add(path, value, def)
+	}
+}
+
+// Returns the value as it is shown in the review
+func formatValue(v reflect.Value) string {
+	var s string
+	switch v.Kind() {
+	case reflect.Slice:
+		var items []string
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, fmt.Sprint(v.Index(i).Interface()))
+		}
+		s = strings.Join(items, ", ")
+	case reflect.Map:
+		var pairs []string
+		iter := v.MapRange()
+		for iter.Next() {
+			pairs = append(pairs, fmt.Sprintf("%v=%v", iter.Key().Interface(), iter.Value().Interface()))
+		}
+		sort.Strings(pairs)
+		s = strings.Join(pairs, ", ")
+	default:
+		s = fmt.Sprint(v.Interface())
+	}
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+/*
+Returns the settings which are allowed but are likely a mistake, like
+two default storage classes. Unlike validateTemplate the values can
+still be saved with warnings. Each warning names a key buildValues
+writes to the values file for the setting, so it can be found there.
+*/
+func reviewWarnings(t Template) []validationError {
+	var warnings []validationError
+	add := func(field string, format string, a ...interface{}) {
+		warnings = append(warnings, validationError{Field: field, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if t.Storage.Nfs.Enabled && t.Storage.Nfs.DefaultSc && t.Storage.Hostpath.Enabled && t.Storage.Hostpath.DefaultSc {
+		add("storage.hostpath.defaultSc", "both the NFS and the HostPath storage classes are set as the default storage class")
+	}
+	if t.Storage.Nfs.Enabled && t.Storage.Nfs.ReclaimPolicy == "Delete" {
+		add("storage.nfs.reclaimPolicy", "Delete removes the data on the NFS server when a volume is deleted")
+	}
+	if t.Storage.Hostpath.Enabled && t.Storage.Hostpath.ReclaimPolicy == "Delete" {
+		add("storage.hostpath.reclaimPolicy", "Delete removes the data on the node when a volume is deleted")
+	}
+	if t.Network.Https.Enabled && t.Network.Https.CertSecret == "" {
+		add("networking.https.enabled", "HTTPS is enabled without a certificate, networking.https.certSecret is not set")
+	}
+	if t.Network.Istio.Enabled && t.Network.Ingress.Type != "" && t.Network.Ingress.Type != "istio" {
+		add("networking.ingress.type", "is %v but istio is still deployed", t.Network.Ingress.Type)
+	}
+	if !t.Dbs.EsEnable && (t.Logging.KibanaEnable || t.Logging.ElastalertEnable) {
+		add("dbs.es.enabled", "Elastic Search is disabled but Kibana or Elastalert is enabled")
+	}
+	if !t.Monitoring.PrometheusEnable && t.Monitoring.GrafanaEnable {
+		add("monitoring.prometheus.enabled", "Prometheus is disabled but Grafana is enabled")
+	}
+	if t.Registry.Enabled && t.Registry.User != "" && t.Registry.Password == "" {
+		add("registry.user", "is set but registry.password is not")
+	}
+	return warnings
+}
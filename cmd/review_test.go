@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Every top level struct of the Template is listed under a section of the review
+func TestReviewSections(t *testing.T) {
+	names := map[string]bool{}
+	for _, section := range tuiSections {
+		names[section.name] = true
+	}
+	tt := reflect.TypeOf(Template{})
+	for i := 0; i < tt.NumField(); i++ {
+		if section := reviewSections[tt.Field(i).Name]; !names[section] {
+			t.Errorf("%v is listed under the unknown section %q", tt.Field(i).Name, section)
+		}
+	}
+}
+
+// Values without a field in the full screen UI are still listed, under the section of their struct
+func TestChangedValues(t *testing.T) {
+	tmpl := defaultTemplate()
+	tmpl.ClusterDomain.ClusterDomain = "example.com"
+	tmpl.Network.Ingress.IstioGwEnabled = false
+	tmpl.Registry.Password = "secret"
+	tmpl.Labels = Labels{}
+
+	changed := changedValues(maskSecrets(tmpl))
+	want := map[string]changedValue{
+		"Cluster Domain":                 {value: "example.com", def: "(none)"},
+		"Network.Ingress.IstioGwEnabled": {value: "false", def: "true"},
+		"Password":                       {value: maskedValue, def: "(none)"},
+	}
+	sections := map[string]string{"Cluster Domain": "Cluster", "Network.Ingress.IstioGwEnabled": "Networking", "Password": "Registry"}
+	found := 0
+	for section, values := range changed {
+		for _, c := range values {
+			w, ok := want[c.label]
+			if !ok {
+				t.Errorf("unexpected change %v: %v", c.label, c.value)
+				continue
+			}
+			found++
+			if c.value != w.value || c.def != w.def {
+				t.Errorf("%v is %v (default: %v), want %v (default: %v)", c.label, c.value, c.def, w.value, w.def)
+			}
+			if section != sections[c.label] {
+				t.Errorf("%v is in the %v section, want %v", c.label, section, sections[c.label])
+			}
+		}
+	}
+	if found != len(want) {
+		t.Errorf("found %d of the %d changes", found, len(want))
+	}
+}
+
+// Every warning names a key which is written to the values file
+func TestReviewWarningPaths(t *testing.T) {
+	tmpl := defaultTemplate()
+	tmpl.Storage.Nfs = Nfs{Enabled: true, Server: "10.0.0.5", Path: "/exports", DefaultSc: true, ReclaimPolicy: "Delete"}
+	tmpl.Storage.Hostpath.Enabled = true
+	tmpl.Storage.Hostpath.DefaultSc = true
+	tmpl.Storage.Hostpath.ReclaimPolicy = "Delete"
+	tmpl.Network.Https.Enabled = true
+	tmpl.Network.Ingress.Type = "nodeport"
+	tmpl.Dbs.EsEnable = false
+	tmpl.Monitoring.PrometheusEnable = false
+	tmpl.Registry = Registry{Enabled: true, Url: "registry.example.com", User: "admin"}
+
+	warnings := reviewWarnings(tmpl)
+	if len(warnings) != 8 {
+		t.Errorf("found %d warnings, want 8: %v", len(warnings), warnings)
+	}
+	values := buildValues(&tmpl)
+	for _, w := range warnings {
+		if _, ok := values.lookup(strings.Split(w.Field, ".")); !ok {
+			t.Errorf("the warning %q names %v, which is not in the values file", w.Message, w.Field)
+		}
+	}
+}
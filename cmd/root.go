@@ -25,12 +25,16 @@ to quickly create a Cobra application.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setupLogging()
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	err := rootCmd.Execute()
+	closeLogging()
 	if err != nil {
 		os.Exit(1)
 	}
@@ -42,6 +46,10 @@ func init() {
 	// will be global for your application.
 
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cnvrg-deploy-cli.yaml)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "file to append the logs to, no logs are written when empty")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "lowest level of the logs written [info|warning|error]")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "format of the logs [text|json]")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "write the logs to stderr")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -0,0 +1,130 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// Set by the flags of the save command
+var (
+	bundleFile   string
+	bundleValues string
+	skopeoBinary string
+)
+
+// saveCmd represents the save command
+var saveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save the images referenced by a values file to an air-gapped bundle",
+	Long: `Copy every image the values file deploys, the same images as
+'images list', into a single OCI image-layout archive which can be
+carried across an air gap and loaded with 'images import', for example:
+
+  cnvrg-deploy-cli images save --values values.yaml --output bundle.tar
+
+The images are copied with skopeo, which must be installed.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		InfoLogger.Println("In the save command")
+		t, err := readValuesFile(bundleValues)
+		if err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
+		images, err := requiredImages(t)
+		if err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
+		return saveBundle(images, bundleFile)
+	},
+}
+
+func init() {
+	imagesCmd.AddCommand(saveCmd)
+
+	saveCmd.Flags().StringVarP(&bundleFile, "output", "o", "bundle.tar", "name of the bundle to create")
+	saveCmd.Flags().StringVar(&bundleValues, "values", "values.yaml", "values file referencing the images to save")
+	saveCmd.Flags().StringVar(&skopeoBinary, "skopeo", "skopeo", "skopeo binary used to copy the images")
+	saveCmd.Flags().StringVar(&catalogSource, "catalog", "", "file or URL of the release catalog the default images are taken from, the embedded catalog when empty")
+}
+
+// Copies the images into an OCI image layout and archives it to the bundle
+func saveBundle(images []ManifestImage, name string) error {
+	dir, err := os.MkdirTemp("", "cnvrg-bundle")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	for i, m := range images {
+		fmt.Printf("%v [%d/%d] Saving %v\n", colorBlue, i+1, len(images), m.Image)
+		if err := runCommand(skopeoBinary, "copy", "docker://"+m.Image, "oci:"+dir+":"+m.Image); err != nil {
+			ErrorLogger.Printf("Failed to save %v: %v\n", m.Image, err)
+			return fmt.Errorf("unable to save %v: %w", m.Image, err)
+		}
+	}
+
+	if err := writeTar(dir, name); err != nil {
+		ErrorLogger.Println(err)
+		return err
+	}
+	fmt.Printf("%v Saved %d images to %v\n", colorGreen, len(images), name)
+	return nil
+}
+
+// Writes every file in the directory to a tar archive
+func writeTar(dir string, name string) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(file)
+
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	// The archive is only complete once the file is flushed and closed
+	return file.Close()
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// A skopeo script which writes the copied reference to a file of the OCI layout
+const fakeSkopeo = `#!/bin/sh
+dest="${3#oci:}"
+dir="${dest%%:*}"
+mkdir -p "$dir/refs"
+echo "${dest#*:}" >> "$dir/refs/copied"
+`
+
+// A values file which only sets the cluster domain saves the default images
+func TestSaveBundleDefaultValues(t *testing.T) {
+	dir := t.TempDir()
+	skopeo := filepath.Join(dir, "skopeo")
+	if err := os.WriteFile(skopeo, []byte(fakeSkopeo), 0755); err != nil {
+		t.Fatal(err)
+	}
+	values := filepath.Join(dir, "values.yaml")
+	if err := os.WriteFile(values, []byte("clusterDomain: example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := []string{bundleFile, bundleValues, skopeoBinary}
+	t.Cleanup(func() { bundleFile, bundleValues, skopeoBinary = old[0], old[1], old[2] })
+	bundleFile = filepath.Join(dir, "bundle.tar")
+	bundleValues = values
+	skopeoBinary = skopeo
+
+	if err := saveCmd.RunE(saveCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(bundleFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var copied []string
+	tr := tar.NewReader(file)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.Name == "refs/copied" {
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			copied = strings.Fields(string(data))
+		}
+	}
+	images, err := requiredImages(defaultTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for _, m := range images {
+		want = append(want, m.Image)
+	}
+	sort.Strings(copied)
+	sort.Strings(want)
+	if len(want) == 0 || strings.Join(copied, " ") != strings.Join(want, " ") {
+		t.Errorf("the bundle holds\n%v\nwant\n%v", copied, want)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import "fmt"
+
+// A Control Plane service which can be scaled
+type scalingService struct {
+	name  string
+	label string
+	// Returns the replicas of the service, nil when they can not be set
+	replicas  func(cp *ControlPlane) *int
+	hpaEnable func(cp *ControlPlane) *bool
+	hpaMax    func(cp *ControlPlane) *int
+}
+
+// The Control Plane services which can be scaled
+var scalingServices = []scalingService{
+	{name: "webapp", label: "Webapp",
+		replicas:  func(cp *ControlPlane) *int { return &cp.WebappReplicas },
+		hpaEnable: func(cp *ControlPlane) *bool { return &cp.WebappHpaEnable },
+		hpaMax:    func(cp *ControlPlane) *int { return &cp.WebappHpaMaxReplicas }},
+	{name: "sidekiq", label: "Sidekiq",
+		hpaEnable: func(cp *ControlPlane) *bool { return &cp.SidekiqHpaEnable },
+		hpaMax:    func(cp *ControlPlane) *int { return &cp.SidekiqHpaMaxReplicas }},
+	{name: "searchkiq", label: "Searchkiq",
+		hpaEnable: func(cp *ControlPlane) *bool { return &cp.SearchkiqHpaEnable },
+		hpaMax:    func(cp *ControlPlane) *int { return &cp.SearchkiqHpaMaxReplicas }},
+	{name: "systemkiq", label: "Systemkiq",
+		hpaEnable: func(cp *ControlPlane) *bool { return &cp.SystemkiqHpaEnable },
+		hpaMax:    func(cp *ControlPlane) *int { return &cp.SystemkiqHpaMaxReplicas }},
+}
+
+// Returns the replicas of the service, 0 when they are not set or can not be set
+func (s scalingService) currentReplicas(cp *ControlPlane) int {
+	if s.replicas == nil {
+		return 0
+	}
+	return *s.replicas(cp)
+}
+
+// Returns a message when the max replicas of the HPA are not valid for the service
+func checkHpaMaxReplicas(s scalingService, cp *ControlPlane, max int) string {
+	if max < 1 {
+		return "must be at least 1"
+	}
+	if replicas := s.currentReplicas(cp); replicas > max {
+		return fmt.Sprintf("must be greater than or equal to the %d replicas", replicas)
+	}
+	return ""
+}
+
+// Adds an error for every replica and HPA value which does not make sense
+func validateScaling(cp ControlPlane, add func(string, string, ...interface{})) {
+	for _, s := range scalingServices {
+		prefix := "controlPlane." + s.name
+		replicas := s.currentReplicas(&cp)
+		if replicas < 0 {
+			add(prefix+".replicas", "must not be negative, got %d", replicas)
+		}
+		max := *s.hpaMax(&cp)
+		if max == 0 {
+			continue
+		}
+		if !*s.hpaEnable(&cp) {
+			add(prefix+".hpa.maxReplicas", "is not used when the hpa is disabled")
+			continue
+		}
+		if msg := checkHpaMaxReplicas(s, &cp, max); msg != "" {
+			add(prefix+".hpa.maxReplicas", "%v, got %d", msg, max)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Shown in place of a secret value on the console
+const maskedValue = "********"
+
+// A secret value starting with the prefix references an existing Secret, secretRef:<name>/<key>
+const secretRefPrefix = "secretRef:"
+
+// Set by the flags of the values command
+var (
+	secretsMode string
+	secretsFile string
+)
+
+// How the secret values are written
+const (
+	// The secret values are written into the values file
+	secretsInline = "inline"
+	// The secret values are written to a second values file, under the same keys of the chart
+	secretsSeparate = "file"
+	// The secret values are written to Secret manifests and the values file references them
+	secretsManifest = "manifest"
+	// The values file references Secrets which already exist in the cluster
+	secretsExisting = "existing"
+)
+
+// A value which must not be written to the console, or to the values file
+// in the manifest and existing secrets modes
+type secretField struct {
+	// Path of the value and of the reference to its Secret in the values file
+	path string
+	ref  string
+	// Name of the Secret and the key holding the value
+	secret string
+	key    string
+	field  func(t *Template) *string
+	// Returns true when the value is needed by the values
+	used func(t *Template) bool
+}
+
+// Every secret value in the Template
+var secretFields = []secretField{
+	{path: "registry.password", ref: "registry.passwordSecretRef",
+		secret: "cnvrg-registry-secret", key: "password",
+		field: func(t *Template) *string { return &t.Registry.Password },
+		used:  func(t *Template) bool { return t.Registry.User != "" }},
+	{path: "sso.clientSecret", ref: "sso.clientSecretRef",
+		secret: "cnvrg-sso-secret", key: "clientSecret",
+		field: func(t *Template) *string { return &t.Sso.ClientSecret },
+		used:  func(t *Template) bool { return t.Sso.Enabled }},
+	{path: "controlPlane.smtp.password", ref: "controlPlane.smtp.passwordSecretRef",
+		secret: "cnvrg-smtp-secret", key: "password",
+		field: func(t *Template) *string { return &t.ControlPlane.SmtpPassword },
+		used:  func(t *Template) bool { return t.ControlPlane.SmtpUsername != "" }},
+	{path: "controlPlane.objectStorage.secretKey", ref: "controlPlane.objectStorage.secretKeyRef",
+		secret: "cnvrg-object-storage-secret", key: "secretKey",
+		field: func(t *Template) *string { return &t.ControlPlane.ObjectStorageSecretKey },
+		used:  func(t *Template) bool { return t.ControlPlane.ObjectStorageAccessKey != "" }},
+	{path: "controlPlane.mpi.registry.password", ref: "controlPlane.mpi.registry.passwordSecretRef",
+		secret: "cnvrg-mpi-registry-secret", key: "password",
+		field: func(t *Template) *string { return &t.ControlPlane.MpiRegistryPassword },
+		used:  func(t *Template) bool { return t.ControlPlane.MpiRegistryUser != "" }},
+}
+
+// Reference to the key of a Secret, written into the values in place of the value
+type secretRef struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+// A Kubernetes Secret manifest
+type secretManifest struct {
+	ApiVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   secretMetadata    `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	StringData map[string]string `yaml:"stringData"`
+}
+
+// Used in the secretManifest struct
+type secretMetadata struct {
+	Name string `yaml:"name"`
+}
+
+// Checks the secrets mode is one of the supported modes
+func validateSecretsMode(mode string) error {
+	switch mode {
+	case secretsInline, secretsSeparate, secretsManifest, secretsExisting:
+		return nil
+	}
+	return fmt.Errorf("unknown secrets mode %v, use one of [%v|%v|%v|%v]", mode, secretsManifest, secretsExisting, secretsSeparate, secretsInline)
+}
+
+// Returns the Secret referenced by a secret value of the form secretRef:<name>/<key>
+func parseSecretRef(value string) (*secretRef, bool) {
+	if !strings.HasPrefix(value, secretRefPrefix) {
+		return nil, false
+	}
+	name, key, ok := strings.Cut(strings.TrimPrefix(value, secretRefPrefix), "/")
+	if !ok || name == "" || key == "" {
+		return nil, false
+	}
+	return &secretRef{Name: name, Key: key}, true
+}
+
+// Returns the secret value referencing the key of a Secret
+func formatSecretRef(name string, key string) string {
+	return secretRefPrefix + name + "/" + key
+}
+
+// Returns true when the secret value is written to the values file in plain text
+func isPlainSecret(value string) bool {
+	_, ok := parseSecretRef(value)
+	return value != "" && !ok
+}
+
+/*
+Returns a copy of the Template with the secret values the secrets mode
+keeps out of the values file replaced. The file mode leaves them out,
+the manifest mode references the Secrets written to the secrets file
+and the existing mode references a Secret for every secret value which
+is set or needed. Values which already reference a Secret are kept.
+*/
+func valuesSecrets(t Template) Template {
+	for _, s := range secretFields {
+		p := s.field(&t)
+		switch {
+		case secretsMode == secretsSeparate && isPlainSecret(*p):
+			*p = ""
+		case secretsMode == secretsManifest && isPlainSecret(*p),
+			secretsMode == secretsExisting && (isPlainSecret(*p) || (*p == "" && s.used(&t))):
+			*p = formatSecretRef(s.secret, s.key)
+		}
+	}
+	return t
+}
+
+/*
+Renders the secrets file, a values file holding only the secret values
+under the same keys as the values file would. Helm merges it with the
+values file when both are passed with --values.
+*/
+func renderSecrets(t *Template) ([]byte, error) {
+	values := buildValues(t)
+	secrets := newValuesMapping()
+	for _, s := range secretFields {
+		path := strings.Split(s.path, ".")
+		if value, ok := values.lookup(path); ok {
+			secrets.setPath(path, value)
+		}
+	}
+	return encodeValues(secrets)
+}
+
+// Returns the Secret manifests holding the secret values of the Template
+func secretManifests(t *Template) []secretManifest {
+	var manifests []secretManifest
+	index := map[string]int{}
+	for _, s := range secretFields {
+		value := *s.field(t)
+		if !isPlainSecret(value) {
+			continue
+		}
+		i, ok := index[s.secret]
+		if !ok {
+			i = len(manifests)
+			index[s.secret] = i
+			manifests = append(manifests, secretManifest{ApiVersion: "v1", Kind: "Secret",
+				Metadata: secretMetadata{Name: s.secret}, Type: "Opaque", StringData: map[string]string{}})
+		}
+		manifests[i].StringData[s.key] = value
+	}
+	return manifests
+}
+
+// Renders the Secret manifests as a single YAML stream
+func renderManifests(manifests []secretManifest) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, m := range manifests {
+		if err := encoder.Encode(m); err != nil {
+			return nil, err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+/*
+Writes the secret values the secrets mode keeps out of the values file.
+The file mode writes them to a second values file and the manifest mode
+to Secret manifests, both readable by the owner only. The existing mode
+lists the Secrets the values file references, and the inline mode warns
+that the passwords were written in plain text.
+*/
+func createSecretsFile(name string, t *Template) error {
+	var data []byte
+	var err error
+	switch secretsMode {
+	case secretsSeparate:
+		data, err = renderSecrets(t)
+	case secretsManifest:
+		manifests := secretManifests(t)
+		if len(manifests) == 0 {
+			return nil
+		}
+		data, err = renderManifests(manifests)
+	case secretsExisting:
+		values := valuesSecrets(*t)
+		for _, s := range secretFields {
+			// Only the references added by the cli, not the ones given as answers
+			if ref, ok := parseSecretRef(*s.field(&values)); ok && *s.field(&values) != *s.field(t) {
+				fmt.Printf("%v Create the Secret %v with the key %v before installing\n", colorYellow, ref.Name, ref.Key)
+			}
+		}
+		return nil
+	default:
+		for _, s := range secretFields {
+			if isPlainSecret(*s.field(t)) {
+				fmt.Printf("%v Warning: the passwords are written to %v in plain text, use --secrets manifest or --secrets existing to keep them out of it\n", colorYellow, valuesFile)
+				break
+			}
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	InfoLogger.Printf("Writing the secret values to %v\n", name)
+	if err := os.WriteFile(name, data, 0600); err != nil {
+		return err
+	}
+	if secretsMode == secretsManifest {
+		fmt.Printf("%v Wrote the Secrets to %v, apply them in the namespace of the release before installing:\n", colorGreen, name)
+		fmt.Printf("%v kubectl apply -n <namespace> -f %v\n", colorWhite, name)
+		return nil
+	}
+	fmt.Printf("%v Wrote the secret values to %v, pass it along with the values file when installing\n", colorGreen, name)
+	return nil
+}
+
+// Returns a copy of the Template with the secret values masked, used for console output
+func maskSecrets(t Template) Template {
+	for _, s := range secretFields {
+		if p := s.field(&t); isPlainSecret(*p) {
+			*p = maskedValue
+		}
+	}
+	return t
+}
+
+// Returns true when the values path holds a secret value
+func isSecretPath(path string) bool {
+	for _, s := range secretFields {
+		if s.path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The manifest mode writes the passwords to Secret manifests and only references into the values
+func TestManifestSecrets(t *testing.T) {
+	defer func(mode string) { secretsMode = mode }(secretsMode)
+	secretsMode = secretsManifest
+
+	tmpl := defaultTemplate()
+	fillValues(t, reflect.ValueOf(&tmpl).Elem())
+	dir := t.TempDir()
+	values := filepath.Join(dir, "values.yaml")
+	secrets := filepath.Join(dir, "secrets.yaml")
+	if err := createFile(values, &tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if err := createSecretsFile(secrets, &tmpl); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := readValuesFile(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range secretFields {
+		if got, want := *s.field(&parsed), formatSecretRef(s.secret, s.key); got != want {
+			t.Errorf("%v is written to the values file as %q, want %q", s.path, got, want)
+		}
+	}
+
+	data, err := os.ReadFile(secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	found := map[string]string{}
+	for {
+		var m secretManifest
+		if err := decoder.Decode(&m); err != nil {
+			break
+		}
+		if m.Kind != "Secret" {
+			t.Errorf("the secrets file holds a %v", m.Kind)
+		}
+		for key, value := range m.StringData {
+			found[m.Metadata.Name+"/"+key] = value
+		}
+	}
+	for _, s := range secretFields {
+		if got, want := found[s.secret+"/"+s.key], *s.field(&tmpl); got != want {
+			t.Errorf("the Secret %v holds %q for %v, want %q", s.secret, got, s.path, want)
+		}
+	}
+}
+
+// The existing mode references a Secret for every password which is needed, even when it is not set
+func TestExistingSecrets(t *testing.T) {
+	defer func(mode string) { secretsMode = mode }(secretsMode)
+	secretsMode = secretsExisting
+
+	tmpl := defaultTemplate()
+	tmpl.ClusterDomain.ClusterDomain = "example.com"
+	tmpl.Registry = Registry{Enabled: true, Url: "registry.example.com", User: "admin"}
+	tmpl.Sso = Sso{Enabled: true, ClientId: "cnvrg", ClientSecret: "secret"}
+	tmpl.ControlPlane.SmtpPassword = "secretRef:smtp/pass"
+
+	data, err := renderValues(&tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parseValues(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"registry.password":                    "secretRef:cnvrg-registry-secret/password",
+		"sso.clientSecret":                     "secretRef:cnvrg-sso-secret/clientSecret",
+		"controlPlane.smtp.password":           "secretRef:smtp/pass",
+		"controlPlane.objectStorage.secretKey": "",
+		"controlPlane.mpi.registry.password":   "",
+	}
+	for _, s := range secretFields {
+		if got := *s.field(&parsed); got != want[s.path] {
+			t.Errorf("%v is written as %q, want %q", s.path, got, want[s.path])
+		}
+	}
+}
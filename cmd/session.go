@@ -0,0 +1,143 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Set by the flags of the values command
+var (
+	recordFile string
+	replayFile string
+)
+
+// The answers given to the menus, in the order they were asked
+type session struct {
+	Answers []sessionAnswer `yaml:"answers"`
+}
+
+// Used in the session struct, passwords are only recorded when they reference a Secret.
+// The answers to the menus are matched by the title of the menu as well as the prompt.
+type sessionAnswer struct {
+	Menu   string `yaml:"menu,omitempty"`
+	Prompt string `yaml:"prompt"`
+	Answer string `yaml:"answer,omitempty"`
+	Secret bool   `yaml:"secret,omitempty"`
+}
+
+const sessionHeader = `# Recorded with 'cnvrg-deploy-cli create values --record', replay it with
+# 'cnvrg-deploy-cli create values --replay <file>'. Passwords are not recorded
+# unless they reference a Secret, the others are asked for again on replay.
+`
+
+// The session being recorded and the session being replayed, nil when not used
+var (
+	recording *session
+	replaying *session
+	replayPos int
+	// Title of the menu prompting for a selection, set by promptMenu
+	currentMenu string
+)
+
+// Raised from the prompts when the replayed session does not match the
+// menus, runMenus recovers it and returns the error
+type replayError struct {
+	err error
+}
+
+// Loads the session to replay and starts the recording, called before the menus are shown
+func startSession() error {
+	if replayFile != "" {
+		data, err := os.ReadFile(replayFile)
+		if err != nil {
+			return fmt.Errorf("unable to read the session: %w", err)
+		}
+		replaying = &session{}
+		if err := yaml.Unmarshal(data, replaying); err != nil {
+			return fmt.Errorf("unable to parse the session %v: %w", replayFile, err)
+		}
+		InfoLogger.Printf("Replaying %d answers from %v\n", len(replaying.Answers), replayFile)
+	}
+	if recordFile != "" {
+		recording = &session{}
+		InfoLogger.Printf("Recording the answers to %v\n", recordFile)
+		return saveSession()
+	}
+	return nil
+}
+
+// Returns the next answer of the replayed session for the prompt. When the
+// session does not match, the error unwinds the menus up to runMenus.
+func replayAnswer(prompt string, secret bool) (string, bool) {
+	answer, ok, err := nextReplayAnswer(currentMenu, prompt, secret)
+	if err != nil {
+		panic(replayError{err})
+	}
+	return answer, ok
+}
+
+/*
+Returns the next answer of the replayed session for the prompt of the
+menu. An error is returned when the menu or the prompt is not the one
+recorded, the menus changed or the session was recorded with other
+flags. Once every answer is replayed the answers are read from stdin
+again.
+*/
+func nextReplayAnswer(menu string, prompt string, secret bool) (string, bool, error) {
+	if replaying == nil || replayPos >= len(replaying.Answers) {
+		return "", false, nil
+	}
+	a := replaying.Answers[replayPos]
+	if a.Menu != menu || a.Prompt != prompt || a.Secret != secret {
+		return "", false, fmt.Errorf("the session does not match the menus at answer %d, expected %q but the prompt is %q",
+			replayPos+1, sessionPrompt(a.Menu, a.Prompt), sessionPrompt(menu, prompt))
+	}
+	replayPos++
+	if secret && a.Answer == "" {
+		// The password was not recorded, it is asked for again
+		return "", false, nil
+	}
+	return a.Answer, true, nil
+}
+
+// Returns the prompt as it is shown in errors, with the title of its menu
+func sessionPrompt(menu string, prompt string) string {
+	if menu == "" {
+		return prompt
+	}
+	return menu + ": " + prompt
+}
+
+// Adds the answer to the recorded session
+func recordAnswer(prompt string, answer string, secret bool) {
+	if recording == nil {
+		return
+	}
+	a := sessionAnswer{Menu: currentMenu, Prompt: prompt, Answer: answer, Secret: secret}
+	if secret && isPlainSecret(answer) {
+		a.Answer = ""
+	}
+	recording.Answers = append(recording.Answers, a)
+	if err := saveSession(); err != nil {
+		ErrorLogger.Println(err)
+		fmt.Println((colorYellow), err)
+	}
+}
+
+// Writes the recorded session, it is saved after every answer so an interrupted session is kept
+func saveSession() error {
+	data, err := yaml.Marshal(recording)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(recordFile, append([]byte(sessionHeader), data...), 0644); err != nil {
+		return fmt.Errorf("unable to write the session: %w", err)
+	}
+	return nil
+}
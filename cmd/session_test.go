@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Sets the registry in the Advanced Options, goes back into the Cluster Domain
+// from the review and saves. The password was added to the recorded session.
+const registrySession = `answers:
+    - menu: Main Menu
+      prompt: 'Please make your selection: '
+      answer: "2"
+    - menu: Advanced Options Menu
+      prompt: 'Please make your selection: '
+      answer: "4"
+    - menu: Registry Menu
+      prompt: 'Please make your selection: '
+      answer: "1"
+    - prompt: 'Input the registry URL [default docker.io]: '
+      answer: registry.example.com
+    - menu: Registry Menu
+      prompt: 'Please make your selection: '
+      answer: "2"
+    - prompt: 'Input the registry User Name: '
+      answer: admin
+    - menu: Registry Menu
+      prompt: 'Please make your selection: '
+      answer: "3"
+    - prompt: 'Input the registry Password: '
+      answer: secret
+      secret: true
+    - menu: Registry Menu
+      prompt: 'Please make your selection: '
+      answer: "4"
+    - menu: Advanced Options Menu
+      prompt: 'Please make your selection: '
+      answer: "14"
+    - menu: Main Menu
+      prompt: 'Please make your selection: '
+      answer: "3"
+    - menu: Review Menu
+      prompt: 'Please make your selection: '
+      answer: "2"
+    - menu: Review Sections Menu
+      prompt: 'Please make your selection: '
+      answer: "1"
+    - prompt: 'What is your wildcard domain? '
+      answer: example.com
+    - menu: Review Menu
+      prompt: 'Please make your selection: '
+      answer: "1"
+`
+
+// Resets the menus and the session when the test is done
+func resetSession(t *testing.T) {
+	old := []string{replayFile, recordFile, valuesFile}
+	t.Cleanup(func() {
+		replayFile, recordFile, valuesFile = old[0], old[1], old[2]
+		replaying, recording, replayPos = nil, nil, 0
+		changes = nil
+		applyTemplate(defaultTemplate())
+	})
+	applyTemplate(defaultTemplate())
+}
+
+// The menus are answered from the replayed session and the values file is written
+func TestReplaySession(t *testing.T) {
+	resetSession(t)
+	dir := t.TempDir()
+	replayFile = filepath.Join(dir, "session.yaml")
+	recordFile = ""
+	valuesFile = filepath.Join(dir, "values.yaml")
+	if err := os.WriteFile(replayFile, []byte(registrySession), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := startSession(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runMenus(mainMenu); err != nil {
+		t.Fatal(err)
+	}
+	if replayPos != len(replaying.Answers) {
+		t.Errorf("replayed %d of the %d answers", replayPos, len(replaying.Answers))
+	}
+	values, err := readValuesFile(valuesFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := defaultTemplate()
+	want.ClusterDomain.ClusterDomain = "example.com"
+	want.Registry = Registry{Enabled: true, Url: "registry.example.com", User: "admin", Password: "secret"}
+	compareTemplates(t, "replayed", values, want)
+}
+
+// A session which no longer matches the menus returns an error without moving on
+func TestReplaySessionMismatch(t *testing.T) {
+	resetSession(t)
+	replaying = &session{Answers: []sessionAnswer{
+		{Prompt: "Input the registry URL [default docker.io]: ", Answer: "registry.example.com"},
+		{Prompt: "Input the registry Password: ", Secret: true},
+	}}
+
+	_, ok, err := nextReplayAnswer("Registry Menu", "Please make your selection: ", false)
+	if err == nil || ok {
+		t.Fatal("the answer to another prompt was replayed")
+	}
+	if !strings.Contains(err.Error(), "answer 1") {
+		t.Errorf("the error does not name the answer: %v", err)
+	}
+	if replayPos != 0 {
+		t.Errorf("the session moved on to answer %d", replayPos+1)
+	}
+
+	if answer, ok, err := nextReplayAnswer("", "Input the registry URL [default docker.io]: ", false); err != nil || !ok || answer != "registry.example.com" {
+		t.Errorf("the recorded answer was not replayed: %q %v %v", answer, ok, err)
+	}
+	// The prompt matches but a password was recorded for it
+	if _, _, err := nextReplayAnswer("", "Input the registry Password: ", false); err == nil {
+		t.Error("a password answer was replayed for a prompt which is not a password")
+	}
+}
+
+// An answer recorded for another menu is not fed to the menu with the same prompt,
+// the error is returned from the menus
+func TestReplaySessionOtherMenu(t *testing.T) {
+	resetSession(t)
+	replaying = &session{Answers: []sessionAnswer{
+		{Menu: "Main Menu", Prompt: "Please make your selection: ", Answer: "2"},
+		{Menu: "Registry Menu", Prompt: "Please make your selection: ", Answer: "1"},
+	}}
+
+	err := runMenus(mainMenu)
+	if err == nil {
+		t.Fatal("the answer of the Registry Menu was replayed in another menu")
+	}
+	if !strings.Contains(err.Error(), "answer 2") || !strings.Contains(err.Error(), "Advanced Options Menu") {
+		t.Errorf("the error does not name the answer and the menu: %v", err)
+	}
+	if replayPos != 1 {
+		t.Errorf("the session moved on to answer %d", replayPos+1)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// The OpenSSL verify modes supported by the cnvrg.io mailer
+var smtpVerifyModes = []string{"none", "peer", "client_once", "fail_if_no_peer_cert"}
+
+// How long to wait for the SMTP server when testing it
+const smtpTimeout = 10 * time.Second
+
+// Returns true when the port is a valid TCP port
+func validSmtpPort(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
+// Returns true when the mode is one of the supported OpenSSL verify modes
+func validSmtpVerifyMode(mode string) bool {
+	for _, m := range smtpVerifyModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true when the value is a bare email address, like cnvrg@example.com
+func validEmail(value string) bool {
+	addr, err := mail.ParseAddress(value)
+	return err == nil && addr.Address == value
+}
+
+// Returns true when any SMTP value is set
+func smtpConfigured(cp ControlPlane) bool {
+	return cp.SmtpServer != "" || cp.SmtpPort != 0 || cp.SmtpUsername != "" || cp.SmtpPassword != "" ||
+		cp.SmtpDomain != "" || cp.SmtpOpenSslMode != "" || cp.SmtpSender != ""
+}
+
+/*
+Connects to the SMTP server, upgrades the connection with STARTTLS
+when the server offers it and logs in when a user name is set. The
+OpenSSL verify mode 'none' skips the verification of the certificate.
+*/
+func testSmtp(cp ControlPlane) error {
+	if cp.SmtpServer == "" {
+		return fmt.Errorf("no SMTP server is configured")
+	}
+	port := cp.SmtpPort
+	if port == 0 {
+		port = 587
+	}
+	address := net.JoinHostPort(cp.SmtpServer, strconv.Itoa(port))
+	InfoLogger.Printf("Testing the SMTP server %v\n", address)
+
+	conn, err := net.DialTimeout("tcp", address, smtpTimeout)
+	if err != nil {
+		return fmt.Errorf("unable to connect to the SMTP server %v: %w", address, err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+	client, err := smtp.NewClient(conn, cp.SmtpServer)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("unable to talk to the SMTP server %v: %w", address, err)
+	}
+	defer client.Close()
+
+	helo := cp.SmtpDomain
+	if helo == "" {
+		helo = "localhost"
+	}
+	if err := client.Hello(helo); err != nil {
+		return fmt.Errorf("the SMTP server refused the greeting: %w", err)
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		config := &tls.Config{ServerName: cp.SmtpServer, InsecureSkipVerify: cp.SmtpOpenSslMode == "none"}
+		if err := client.StartTLS(config); err != nil {
+			return fmt.Errorf("unable to start TLS with the SMTP server: %w", err)
+		}
+	}
+	if cp.SmtpUsername != "" {
+		if _, ok := parseSecretRef(cp.SmtpPassword); ok {
+			fmt.Println((colorYellow), "The SMTP password is stored in a Secret, skipping the login")
+		} else if ok, _ := client.Extension("AUTH"); ok {
+			auth := smtp.PlainAuth("", cp.SmtpUsername, cp.SmtpPassword, cp.SmtpServer)
+			if err := client.Auth(auth); err != nil {
+				return fmt.Errorf("unable to log in to the SMTP server: %w", err)
+			}
+		}
+	}
+	if cp.SmtpSender != "" {
+		if err := client.Mail(cp.SmtpSender); err != nil {
+			return fmt.Errorf("the SMTP server refused the sender %v: %w", cp.SmtpSender, err)
+		}
+		client.Reset()
+	}
+	return client.Quit()
+}
+
+// Tests the SMTP server before the values file is written when --test-smtp is set
+func checkSmtpServer(cp ControlPlane) error {
+	if !testSmtpServer {
+		return nil
+	}
+	if err := testSmtp(cp); err != nil {
+		ErrorLogger.Println(err)
+		return fmt.Errorf("the SMTP server test failed, %v was not written: %w", valuesFile, err)
+	}
+	fmt.Printf("%v Connected to the SMTP server %v\n", colorGreen, cp.SmtpServer)
+	return nil
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A fake SMTP server which serves a single connection
+type fakeSmtp struct {
+	// Offer STARTTLS with the certificate
+	cert *tls.Certificate
+	// The password accepted for AUTH PLAIN and the sender refused by MAIL FROM
+	password     string
+	rejectSender string
+
+	// Set while serving, read once done is closed
+	usedTLS     bool
+	authWithTLS bool
+	done        chan struct{}
+}
+
+// Starts the fake server and returns the control plane values pointing to it
+func startFakeSmtp(t *testing.T, s *fakeSmtp) ControlPlane {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		s.serve(conn)
+	}()
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return ControlPlane{SmtpServer: host, SmtpPort: p}
+}
+
+func (s *fakeSmtp) serve(conn net.Conn) {
+	defer func() { conn.Close() }()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	r := bufio.NewReader(conn)
+	write := func(lines ...string) {
+		for _, line := range lines {
+			fmt.Fprintf(conn, "%s\r\n", line)
+		}
+	}
+	write("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			write("500 empty command")
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "EHLO":
+			if s.cert != nil && !s.usedTLS {
+				write("250-fake", "250-STARTTLS", "250 AUTH PLAIN")
+			} else {
+				write("250-fake", "250 AUTH PLAIN")
+			}
+		case "STARTTLS":
+			write("220 ready to start TLS")
+			tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{*s.cert}})
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			r = bufio.NewReader(conn)
+			s.usedTLS = true
+		case "AUTH":
+			s.authWithTLS = s.usedTLS
+			credentials, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			parts := strings.Split(string(credentials), "\x00")
+			if len(parts) == 3 && parts[2] == s.password {
+				write("235 authenticated")
+			} else {
+				write("535 authentication failed")
+			}
+		case "MAIL":
+			if s.rejectSender != "" && strings.Contains(line, s.rejectSender) {
+				write("550 sender rejected")
+			} else {
+				write("250 ok")
+			}
+		case "RSET":
+			write("250 ok")
+		case "QUIT":
+			write("221 bye")
+			return
+		default:
+			write("502 command not implemented")
+		}
+	}
+}
+
+// Returns a self signed certificate for 127.0.0.1
+func testCertificate(t *testing.T) *tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "fake smtp"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// The connection is upgraded with STARTTLS before logging in
+func TestSmtpStartTLS(t *testing.T) {
+	server := &fakeSmtp{cert: testCertificate(t), password: "secret"}
+	cp := startFakeSmtp(t, server)
+	cp.SmtpOpenSslMode = "none"
+	cp.SmtpUsername = "cnvrg"
+	cp.SmtpPassword = "secret"
+	cp.SmtpSender = "cnvrg@example.com"
+
+	if err := testSmtp(cp); err != nil {
+		t.Fatal(err)
+	}
+	<-server.done
+	if !server.usedTLS || !server.authWithTLS {
+		t.Errorf("the login did not use TLS, STARTTLS: %v, login with TLS: %v", server.usedTLS, server.authWithTLS)
+	}
+}
+
+// The certificate is verified unless the verify mode is none
+func TestSmtpVerifiesCertificate(t *testing.T) {
+	cp := startFakeSmtp(t, &fakeSmtp{cert: testCertificate(t)})
+	cp.SmtpOpenSslMode = "peer"
+
+	err := testSmtp(cp)
+	if err == nil || !strings.Contains(err.Error(), "unable to start TLS") {
+		t.Errorf("the self signed certificate was accepted, got %v", err)
+	}
+}
+
+func TestSmtpAuthFailure(t *testing.T) {
+	cp := startFakeSmtp(t, &fakeSmtp{password: "secret"})
+	cp.SmtpUsername = "cnvrg"
+	cp.SmtpPassword = "wrong"
+
+	err := testSmtp(cp)
+	if err == nil || !strings.Contains(err.Error(), "unable to log in") {
+		t.Errorf("the wrong password was not reported, got %v", err)
+	}
+}
+
+func TestSmtpSenderRejected(t *testing.T) {
+	cp := startFakeSmtp(t, &fakeSmtp{rejectSender: "blocked@example.com"})
+	cp.SmtpSender = "blocked@example.com"
+
+	err := testSmtp(cp)
+	if err == nil || !strings.Contains(err.Error(), "refused the sender") {
+		t.Errorf("the rejected sender was not reported, got %v", err)
+	}
+}
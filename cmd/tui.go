@@ -0,0 +1,735 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Set by the --tui flag of the values command
+var tuiMode bool
+
+// A value which can be edited in the full screen UI
+type tuiField struct {
+	label string
+	// Returns a pointer to the field of the Template, like the field flags
+	field func(t *Template) interface{}
+	// Optional, checks a typed value or every item of a list
+	check func(string) error
+	// Optional, the values are cycled through instead of typed
+	options []string
+	secret  bool
+	// Optional, used to enable the parent section when the value is set
+	enable func(t *Template)
+}
+
+// A section of the tree, the key is the top level key of the values file it is rendered to
+type tuiSection struct {
+	name   string
+	key    string
+	fields []tuiField
+}
+
+var reclaimPolicies = []string{"", "Retain", "Delete", "Recycle"}
+
+// The sections and values of the full screen UI, in the order they are shown
+var tuiSections = []tuiSection{
+	{name: "Cluster", key: "clusterDomain", fields: []tuiField{
+		{label: "Cluster Domain", field: func(t *Template) interface{} { return &t.ClusterDomain.ClusterDomain }},
+		{label: "Image Hub", field: func(t *Template) interface{} { return &t.ClusterDomain.ImageHub }},
+		{label: "Internal Domain", field: func(t *Template) interface{} { return &t.ClusterInteralDomain.Domain }},
+		{label: "Labels", field: func(t *Template) interface{} { return (*map[string]string)(&t.Labels) }},
+		{label: "Annotations", field: func(t *Template) interface{} { return (*map[string]string)(&t.Annotations) }},
+	}},
+	{name: "Networking", key: "networking", fields: []tuiField{
+		{label: "HTTPS", field: func(t *Template) interface{} { return &t.Network.Https.Enabled }},
+		{label: "Certificate Secret", field: func(t *Template) interface{} { return &t.Network.Https.CertSecret },
+			enable: func(t *Template) { t.Network.Https.Enabled = true }},
+		{label: "Ingress Type", options: []string{"", "istio", "ingress", "openshift", "nodeport"},
+			field: func(t *Template) interface{} { return &t.Network.Ingress.Type },
+			enable: func(t *Template) {
+				if t.Network.Ingress.Type != "istio" && t.Network.Ingress.Type != "" {
+					t.Network.Istio.Enabled = false
+				}
+			}},
+		{label: "Istio", field: func(t *Template) interface{} { return &t.Network.Istio.Enabled }},
+		{label: "Istio External IPs", check: checkIP, field: func(t *Template) interface{} { return &t.Network.Istio.ExternalIp }},
+		{label: "Istio Extra Ports", check: checkPort, field: func(t *Template) interface{} { return &t.Network.Istio.IngressSvcExtraPorts }},
+		{label: "Istio LB Source Ranges", check: checkCIDR, field: func(t *Template) interface{} { return &t.Network.Istio.LbSourceRanges }},
+		{label: "Istio Svc Annotations", field: func(t *Template) interface{} { return &t.Network.Istio.IngressSvcAnnotations }},
+		{label: "Proxy", field: func(t *Template) interface{} { return &t.Network.Proxy.Enabled }},
+		{label: "HTTP Proxies", check: checkURL, field: func(t *Template) interface{} { return &t.Network.Proxy.HttpProxy },
+			enable: func(t *Template) { t.Network.Proxy.Enabled = true }},
+		{label: "HTTPS Proxies", check: checkURL, field: func(t *Template) interface{} { return &t.Network.Proxy.HttpsProxy },
+			enable: func(t *Template) { t.Network.Proxy.Enabled = true }},
+		{label: "No Proxy", field: func(t *Template) interface{} { return &t.Network.Proxy.NoProxy },
+			enable: func(t *Template) { t.Network.Proxy.Enabled = true }},
+	}},
+	{name: "Logging", key: "logging", fields: []tuiField{
+		{label: "Fluentbit", field: func(t *Template) interface{} { return &t.Logging.FluentbitEnable }},
+		{label: "Kibana", field: func(t *Template) interface{} { return &t.Logging.KibanaEnable }},
+		{label: "Elastalert", field: func(t *Template) interface{} { return &t.Logging.ElastalertEnable }},
+		{label: "Elastalert Storage Size", check: checkQuantity, field: func(t *Template) interface{} { return &t.Logging.ElastaStorageSize }},
+		{label: "Elastalert Storage Class", field: func(t *Template) interface{} { return &t.Logging.ElastaStorageClass }},
+		{label: "Elastalert Node Selector", field: func(t *Template) interface{} { return &t.Logging.ElastaNodeSelector }},
+	}},
+	{name: "Registry", key: "registry", fields: []tuiField{
+		{label: "URL", field: func(t *Template) interface{} { return &t.Registry.Url },
+			enable: func(t *Template) { t.Registry.Enabled = true }},
+		{label: "User Name", field: func(t *Template) interface{} { return &t.Registry.User },
+			enable: func(t *Template) { t.Registry.Enabled = true }},
+		{label: "Password", secret: true, field: func(t *Template) interface{} { return &t.Registry.Password },
+			enable: func(t *Template) { t.Registry.Enabled = true }},
+	}},
+	{name: "Tenancy", key: "tenancy", fields: []tuiField{
+		{label: "Tenancy", field: func(t *Template) interface{} { return &t.Tenancy.Enabled }},
+		{label: "Node Selector Key", field: func(t *Template) interface{} { return &t.Tenancy.Key },
+			enable: func(t *Template) { t.Tenancy.Enabled = true }},
+		{label: "Node Selector Value", field: func(t *Template) interface{} { return &t.Tenancy.Value },
+			enable: func(t *Template) { t.Tenancy.Enabled = true }},
+	}},
+	{name: "SSO", key: "sso", fields: []tuiField{
+		{label: "Single Sign On", field: func(t *Template) interface{} { return &t.Sso.Enabled }},
+		{label: "Admin User", field: func(t *Template) interface{} { return &t.Sso.AdminUser },
+			enable: func(t *Template) { t.Sso.Enabled = true }},
+		{label: "Provider", field: func(t *Template) interface{} { return &t.Sso.Provider },
+			enable: func(t *Template) { t.Sso.Enabled = true }},
+		{label: "Email Domains", field: func(t *Template) interface{} { return &t.Sso.EmailDomain },
+			enable: func(t *Template) { t.Sso.Enabled = true }},
+		{label: "Client ID", field: func(t *Template) interface{} { return &t.Sso.ClientId },
+			enable: func(t *Template) { t.Sso.Enabled = true }},
+		{label: "Client Secret", secret: true, field: func(t *Template) interface{} { return &t.Sso.ClientSecret },
+			enable: func(t *Template) { t.Sso.Enabled = true }},
+		{label: "Azure Tenant", field: func(t *Template) interface{} { return &t.Sso.AzureTenant },
+			enable: func(t *Template) { t.Sso.Enabled = true }},
+		{label: "OIDC Issuer URL", check: checkURL, field: func(t *Template) interface{} { return &t.Sso.OidcIssuerUrl },
+			enable: func(t *Template) { t.Sso.Enabled = true }},
+	}},
+	{name: "Storage", key: "storage", fields: []tuiField{
+		{label: "HostPath", field: func(t *Template) interface{} { return &t.Storage.Hostpath.Enabled }},
+		{label: "HostPath Default Storage Class", field: func(t *Template) interface{} { return &t.Storage.Hostpath.DefaultSc },
+			enable: func(t *Template) { t.Storage.Hostpath.Enabled = true }},
+		{label: "HostPath Path", field: func(t *Template) interface{} { return &t.Storage.Hostpath.Path },
+			enable: func(t *Template) { t.Storage.Hostpath.Enabled = true }},
+		{label: "HostPath Reclaim Policy", options: reclaimPolicies, field: func(t *Template) interface{} { return &t.Storage.Hostpath.ReclaimPolicy },
+			enable: func(t *Template) { t.Storage.Hostpath.Enabled = true }},
+		{label: "HostPath Node Selector", field: func(t *Template) interface{} { return &t.Storage.Hostpath.NodeSelector },
+			enable: func(t *Template) { t.Storage.Hostpath.Enabled = true }},
+		{label: "NFS", field: func(t *Template) interface{} { return &t.Storage.Nfs.Enabled }},
+		{label: "NFS Server", check: checkHost, field: func(t *Template) interface{} { return &t.Storage.Nfs.Server },
+			enable: func(t *Template) { t.Storage.Nfs.Enabled = true }},
+		{label: "NFS Path", field: func(t *Template) interface{} { return &t.Storage.Nfs.Path },
+			enable: func(t *Template) { t.Storage.Nfs.Enabled = true }},
+		{label: "NFS Default Storage Class", field: func(t *Template) interface{} { return &t.Storage.Nfs.DefaultSc },
+			enable: func(t *Template) { t.Storage.Nfs.Enabled = true }},
+		{label: "NFS Reclaim Policy", options: reclaimPolicies, field: func(t *Template) interface{} { return &t.Storage.Nfs.ReclaimPolicy },
+			enable: func(t *Template) { t.Storage.Nfs.Enabled = true }},
+	}},
+	{name: "Misc", key: "backup", fields: []tuiField{
+		{label: "Backup", field: func(t *Template) interface{} { return &t.Backup.Enabled }},
+		{label: "Backup Rotation", check: checkInt(0, 1000), field: func(t *Template) interface{} { return &t.Backup.Rotation }},
+		{label: "Backup Period", check: checkDuration, field: func(t *Template) interface{} { return &t.Backup.Period }},
+		{label: "Capsule", field: func(t *Template) interface{} { return &t.Capsule.Enabled }},
+		{label: "Capsule Image", field: func(t *Template) interface{} { return &t.Capsule.Image }},
+		{label: "Nvidia GPU", field: func(t *Template) interface{} { return &t.Gpu.NvidiaEnable }},
+		{label: "Habana GPU", field: func(t *Template) interface{} { return &t.Gpu.HabanaEnable }},
+		{label: "Config Reloader", field: func(t *Template) interface{} { return &t.ConfigReloader.Enabled }},
+	}},
+	{name: "Monitoring", key: "monitoring", fields: []tuiField{
+		{label: "DCGM Exporter", field: func(t *Template) interface{} { return &t.Monitoring.DcgmExportEnable }},
+		{label: "Habana Exporter", field: func(t *Template) interface{} { return &t.Monitoring.HabanaExportEnable }},
+		{label: "Node Exporter", field: func(t *Template) interface{} { return &t.Monitoring.NodeExportEnable }},
+		{label: "Kube State Metrics", field: func(t *Template) interface{} { return &t.Monitoring.KubeStateMetricEnable }},
+		{label: "Grafana", field: func(t *Template) interface{} { return &t.Monitoring.GrafanaEnable }},
+		{label: "Prometheus Operator", field: func(t *Template) interface{} { return &t.Monitoring.PrometheusOperatorEnable }},
+		{label: "Prometheus", field: func(t *Template) interface{} { return &t.Monitoring.PrometheusEnable }},
+		{label: "Prometheus Storage Size", check: checkQuantity, field: func(t *Template) interface{} { return &t.Monitoring.PrometheusStorageSize }},
+		{label: "Prometheus Storage Class", field: func(t *Template) interface{} { return &t.Monitoring.PrometheusStorageClass }},
+		{label: "Default Svc Monitors", field: func(t *Template) interface{} { return &t.Monitoring.DefaultSvcMonitorsEnable }},
+		{label: "cnvrg Idle Metrics", field: func(t *Template) interface{} { return &t.Monitoring.CnvrgIdleMetricsEnable }},
+	}},
+	{name: "Control Plane", key: "controlPlane", fields: []tuiField{
+		{label: "Image", field: func(t *Template) interface{} { return &t.ControlPlane.Image }},
+		{label: "Agent Custom Tag", field: func(t *Template) interface{} { return &t.ControlPlane.BaseConfigAgentTag }},
+		{label: "Intercom", field: func(t *Template) interface{} { return &t.ControlPlane.BaseConfigIntercom }},
+		{label: "Privileged Jobs", field: func(t *Template) interface{} { return &t.ControlPlane.BaseConfigCnvrgPrivileged }},
+		{label: "Feature Flags", field: func(t *Template) interface{} { return &t.ControlPlane.BaseConfigFeatureFlags }},
+		{label: "Hyper", field: func(t *Template) interface{} { return &t.ControlPlane.HyperEnable }},
+		{label: "cnvrg Scheduler", field: func(t *Template) interface{} { return &t.ControlPlane.CnvrgScheduleEnable }},
+		{label: "Cluster Provisioner", field: func(t *Template) interface{} { return &t.ControlPlane.CnvrgClusterProvisionerEnable }},
+		{label: "Webapp", field: func(t *Template) interface{} { return &t.ControlPlane.WebappEnable }},
+		{label: "Webapp Replicas", check: checkInt(0, 1000), field: func(t *Template) interface{} { return &t.ControlPlane.WebappReplicas }},
+		{label: "Webapp HPA", field: func(t *Template) interface{} { return &t.ControlPlane.WebappHpaEnable }},
+		{label: "Webapp HPA Max Replicas", check: checkInt(0, 1000), field: func(t *Template) interface{} { return &t.ControlPlane.WebappHpaMaxReplicas }},
+		{label: "Sidekiq", field: func(t *Template) interface{} { return &t.ControlPlane.SidekiqEnable }},
+		{label: "Sidekiq Split", field: func(t *Template) interface{} { return &t.ControlPlane.SidekiqSplit }},
+		{label: "Sidekiq HPA", field: func(t *Template) interface{} { return &t.ControlPlane.SidekiqHpaEnable }},
+		{label: "Sidekiq HPA Max Replicas", check: checkInt(0, 1000), field: func(t *Template) interface{} { return &t.ControlPlane.SidekiqHpaMaxReplicas }},
+		{label: "Searchkiq", field: func(t *Template) interface{} { return &t.ControlPlane.SearchkiqEnable }},
+		{label: "Searchkiq HPA", field: func(t *Template) interface{} { return &t.ControlPlane.SearchkiqHpaEnable }},
+		{label: "Searchkiq HPA Max Replicas", check: checkInt(0, 1000), field: func(t *Template) interface{} { return &t.ControlPlane.SearchkiqHpaMaxReplicas }},
+		{label: "Systemkiq", field: func(t *Template) interface{} { return &t.ControlPlane.SystemkiqEnable }},
+		{label: "Systemkiq HPA", field: func(t *Template) interface{} { return &t.ControlPlane.SystemkiqHpaEnable }},
+		{label: "Systemkiq HPA Max Replicas", check: checkInt(0, 1000), field: func(t *Template) interface{} { return &t.ControlPlane.SystemkiqHpaMaxReplicas }},
+		{label: "SMTP Server", check: checkHost, field: func(t *Template) interface{} { return &t.ControlPlane.SmtpServer }},
+		{label: "SMTP Port", check: checkInt(0, 65535), field: func(t *Template) interface{} { return &t.ControlPlane.SmtpPort }},
+		{label: "SMTP User Name", field: func(t *Template) interface{} { return &t.ControlPlane.SmtpUsername }},
+		{label: "SMTP Password", secret: true, field: func(t *Template) interface{} { return &t.ControlPlane.SmtpPassword }},
+		{label: "SMTP Domain", field: func(t *Template) interface{} { return &t.ControlPlane.SmtpDomain }},
+		{label: "SMTP OpenSSL Verify Mode", options: append([]string{""}, smtpVerifyModes...),
+			field: func(t *Template) interface{} { return &t.ControlPlane.SmtpOpenSslMode }},
+		{label: "SMTP Sender", check: checkEmail, field: func(t *Template) interface{} { return &t.ControlPlane.SmtpSender }},
+		{label: "Object Storage Type", options: append([]string{""}, objectStorageTypes()...),
+			field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageType }},
+		{label: "Object Storage Bucket", field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageBucket }},
+		{label: "Object Storage Region", field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageRegion }},
+		{label: "Object Storage Access Key", field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageAccessKey }},
+		{label: "Object Storage Secret Key", secret: true, field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageSecretKey }},
+		{label: "Object Storage Endpoint", check: checkURL, field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageEndpoint }},
+		{label: "Azure Storage Account Name", field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageAzureAcountName }},
+		{label: "Azure Container", field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageAzureContainer }},
+		{label: "GCP Service Account Secret", field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageGcpSecretRef }},
+		{label: "GCP Project", field: func(t *Template) interface{} { return &t.ControlPlane.ObjectStorageGcpProject }},
+		{label: "MPI", field: func(t *Template) interface{} { return &t.ControlPlane.MpiEnable }},
+		{label: "MPI Image", field: func(t *Template) interface{} { return &t.ControlPlane.MpiImage },
+			enable: func(t *Template) { t.ControlPlane.MpiEnable = true }},
+		{label: "MPI Kubectl Delivery Image", field: func(t *Template) interface{} { return &t.ControlPlane.MpiKubectlImage },
+			enable: func(t *Template) { t.ControlPlane.MpiEnable = true }},
+		{label: "MPI Extra Args", field: func(t *Template) interface{} { return &t.ControlPlane.MpiExtraArgs },
+			enable: func(t *Template) { t.ControlPlane.MpiEnable = true }},
+		{label: "MPI Registry URL", field: func(t *Template) interface{} { return &t.ControlPlane.MpiRegistryUrl },
+			enable: func(t *Template) { t.ControlPlane.MpiEnable = true }},
+		{label: "MPI Registry User Name", field: func(t *Template) interface{} { return &t.ControlPlane.MpiRegistryUser },
+			enable: func(t *Template) { t.ControlPlane.MpiEnable = true }},
+		{label: "MPI Registry Password", secret: true, field: func(t *Template) interface{} { return &t.ControlPlane.MpiRegistryPassword },
+			enable: func(t *Template) { t.ControlPlane.MpiEnable = true }},
+	}},
+	{name: "Databases", key: "dbs", fields: []tuiField{
+		{label: "CVAT", field: func(t *Template) interface{} { return &t.Dbs.CvatEnable }},
+		{label: "Elastic Search", field: func(t *Template) interface{} { return &t.Dbs.EsEnable }},
+		{label: "Elastic Search Storage Size", check: checkQuantity, field: func(t *Template) interface{} { return &t.Dbs.EsStorageSize }},
+		{label: "Elastic Search Storage Class", field: func(t *Template) interface{} { return &t.Dbs.EsStorageClass }},
+		{label: "Elastic Search Patch Nodes", field: func(t *Template) interface{} { return &t.Dbs.EsPatchNodes }},
+		{label: "Elastic Search Node Selector", field: func(t *Template) interface{} { return &t.Dbs.EsNodeSelector }},
+		{label: "Minio", field: func(t *Template) interface{} { return &t.Dbs.MinioEnable }},
+		{label: "Minio Storage Size", check: checkQuantity, field: func(t *Template) interface{} { return &t.Dbs.MinioStorageSize }},
+		{label: "Minio Storage Class", field: func(t *Template) interface{} { return &t.Dbs.MinioStorageClass }},
+		{label: "Minio Node Selector", field: func(t *Template) interface{} { return &t.Dbs.MinioNodeSelector }},
+		{label: "Postgres", field: func(t *Template) interface{} { return &t.Dbs.PgEnable }},
+		{label: "Postgres Storage Size", check: checkQuantity, field: func(t *Template) interface{} { return &t.Dbs.PgStorageSize }},
+		{label: "Postgres Storage Class", field: func(t *Template) interface{} { return &t.Dbs.PgStorageClass }},
+		{label: "Postgres Node Selector", field: func(t *Template) interface{} { return &t.Dbs.PgNodeSelector }},
+		{label: "Redis", field: func(t *Template) interface{} { return &t.Dbs.RedisEnable }},
+		{label: "Redis Storage Size", check: checkQuantity, field: func(t *Template) interface{} { return &t.Dbs.RedisStorageSize }},
+		{label: "Redis Storage Class", field: func(t *Template) interface{} { return &t.Dbs.RedisStorageClass }},
+		{label: "Redis Node Selector", field: func(t *Template) interface{} { return &t.Dbs.RedisNodeSelector }},
+	}},
+}
+
+// Returns the value of the field as it is shown in the tree
+func (f tuiField) display(t *Template) string {
+	switch p := f.field(t).(type) {
+	case *string:
+		if *p == "" {
+			return ""
+		}
+		if f.secret && isPlainSecret(*p) {
+			return maskedValue
+		}
+		return *p
+	case *bool:
+		if *p {
+			return "[x]"
+		}
+		return "[ ]"
+	case *int:
+		return strconv.Itoa(*p)
+	case *[]string:
+		return strings.Join(*p, ", ")
+	case *map[string]string:
+		var pairs []string
+		for k, v := range *p {
+			pairs = append(pairs, k+"="+v)
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ", ")
+	}
+	return ""
+}
+
+// Returns the text the value is edited from, passwords are typed again
+func (f tuiField) text(t *Template) string {
+	if f.secret {
+		return ""
+	}
+	return f.display(t)
+}
+
+// Returns true when the value is switched or cycled instead of typed
+func (f tuiField) toggles(t *Template) bool {
+	_, ok := f.field(t).(*bool)
+	return ok || f.options != nil
+}
+
+// Switches a yes or no value, or moves to the next option
+func (f tuiField) toggle(t *Template) {
+	switch p := f.field(t).(type) {
+	case *bool:
+		*p = !*p
+		return
+	case *string:
+		next := 0
+		for i, o := range f.options {
+			if o == *p {
+				next = (i + 1) % len(f.options)
+			}
+		}
+		*p = f.options[next]
+	}
+	if f.enable != nil {
+		f.enable(t)
+	}
+}
+
+// Checks and sets a typed value, lists are comma separated and maps are key=value pairs
+func (f tuiField) set(t *Template, text string) error {
+	text = strings.TrimSpace(text)
+	check := optional(f.check)
+	switch p := f.field(t).(type) {
+	case *string:
+		if f.secret && text == "" {
+			// Passwords are not shown, no input keeps the current one
+			return nil
+		}
+		if err := check(text); err != nil {
+			return err
+		}
+		*p = text
+	case *int:
+		if text == "" {
+			text = "0"
+		}
+		if err := checkInt(0, 1<<31-1)(text); err != nil {
+			return err
+		}
+		if err := check(text); err != nil {
+			return err
+		}
+		*p, _ = strconv.Atoi(text)
+	case *[]string:
+		var list []string
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			if err := check(item); err != nil {
+				return fmt.Errorf("%v: %w", item, err)
+			}
+			list = append(list, item)
+		}
+		*p = list
+	case *map[string]string:
+		values := map[string]string{}
+		for _, pair := range strings.Split(text, ",") {
+			if pair = strings.TrimSpace(pair); pair == "" {
+				continue
+			}
+			key, value, found := strings.Cut(pair, "=")
+			if !found || strings.TrimSpace(key) == "" {
+				return fmt.Errorf("Please use the format key=value, key=value")
+			}
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+		*p = values
+	}
+	if f.enable != nil && text != "" {
+		f.enable(t)
+	}
+	return nil
+}
+
+// A row of the section tree, a section header when the field is nil
+type tuiRow struct {
+	section int
+	field   *tuiField
+}
+
+// The state of the full screen UI
+type tui struct {
+	screen   tcell.Screen
+	t        Template
+	defaults Template
+	expanded []bool
+	cursor   int
+	top      int
+	// The value being typed, only used while editing
+	editing bool
+	input   []rune
+	// Set while a password is typed a second time to confirm it
+	confirming bool
+	firstEntry []rune
+	message    string
+	isError    bool
+	preview    []string
+	previewTop int
+	quitting   bool
+}
+
+var (
+	styleTitle    = tcell.StyleDefault.Foreground(tcell.ColorGreen).Bold(true)
+	styleSection  = tcell.StyleDefault.Foreground(tcell.ColorBlue).Bold(true)
+	styleValue    = tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	styleDefault  = tcell.StyleDefault.Foreground(tcell.ColorGray)
+	styleSelected = tcell.StyleDefault.Reverse(true)
+	styleError    = tcell.StyleDefault.Foreground(tcell.ColorYellow)
+)
+
+/*
+Shows the values in a full screen UI with a tree of the sections on
+the left and the rendered values file on the right, which is updated
+after every change. Returns the edited Template and true when the user
+saved the values, the values are validated before the UI is left.
+*/
+func runTui(t Template) (Template, bool, error) {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return t, false, fmt.Errorf("the full screen UI needs a terminal: %w", err)
+	}
+	if err := screen.Init(); err != nil {
+		return t, false, fmt.Errorf("the full screen UI needs a terminal: %w", err)
+	}
+	defer screen.Fini()
+
+	u := newTui(screen, t)
+	for {
+		u.draw()
+		switch ev := screen.PollEvent().(type) {
+		case *tcell.EventResize:
+			screen.Sync()
+		case *tcell.EventKey:
+			if done, save := u.handleKey(ev); done {
+				return u.t, save, nil
+			}
+		}
+	}
+}
+
+// Returns the UI for the values on the screen with the first section expanded
+func newTui(screen tcell.Screen, t Template) *tui {
+	u := &tui{screen: screen, t: t, defaults: defaultTemplate(), expanded: make([]bool, len(tuiSections))}
+	u.expanded[0] = true
+	u.message = "enter: edit or toggle  d: default  pgup/pgdn: scroll preview  ctrl-s: save  q: quit"
+	u.refreshPreview()
+	return u
+}
+
+// Returns the rows of the tree, the fields of the expanded sections are listed under them
+func (u *tui) rows() []tuiRow {
+	var rows []tuiRow
+	for i := range tuiSections {
+		rows = append(rows, tuiRow{section: i})
+		if !u.expanded[i] {
+			continue
+		}
+		for j := range tuiSections[i].fields {
+			rows = append(rows, tuiRow{section: i, field: &tuiSections[i].fields[j]})
+		}
+	}
+	return rows
+}
+
+// Renders the values with the secrets masked for the preview pane
+func (u *tui) refreshPreview() {
+	masked := maskSecrets(u.t)
+	data, err := renderValues(&masked)
+	if err != nil {
+		u.preview = []string{err.Error()}
+		return
+	}
+	u.preview = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+}
+
+// Scrolls the preview to the key of the section
+func (u *tui) previewSection(section int) {
+	for i, line := range u.preview {
+		if strings.HasPrefix(line, tuiSections[section].key+":") {
+			u.previewTop = i
+			return
+		}
+	}
+}
+
+// Handles a key press, returns true when the UI is left and true again when the values are saved
+func (u *tui) handleKey(ev *tcell.EventKey) (bool, bool) {
+	rows := u.rows()
+	row := rows[u.cursor]
+	if u.editing {
+		switch ev.Key() {
+		case tcell.KeyEnter:
+			if row.field.secret && len(u.input) > 0 {
+				if !u.confirming {
+					u.confirming, u.firstEntry, u.input = true, u.input, nil
+					u.setMessage("Confirm the value  esc: cancel", false)
+					return false, false
+				}
+				if string(u.firstEntry) != string(u.input) {
+					u.confirming, u.firstEntry, u.input = false, nil, nil
+					u.setMessage("The values do not match, please try again", true)
+					return false, false
+				}
+			}
+			u.confirming, u.firstEntry = false, nil
+			if err := row.field.set(&u.t, string(u.input)); err != nil {
+				u.setMessage(err.Error(), true)
+				return false, false
+			}
+			u.editing = false
+			u.setMessage(row.field.label+" set", false)
+			u.refreshPreview()
+		case tcell.KeyEscape:
+			u.editing, u.confirming, u.firstEntry = false, false, nil
+			u.setMessage("", false)
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if len(u.input) > 0 {
+				u.input = u.input[:len(u.input)-1]
+			}
+		case tcell.KeyCtrlU:
+			u.input = nil
+		case tcell.KeyRune:
+			u.input = append(u.input, ev.Rune())
+		}
+		return false, false
+	}
+
+	if ev.Key() != tcell.KeyRune || ev.Rune() != 'q' {
+		u.quitting = false
+	}
+	switch ev.Key() {
+	case tcell.KeyUp:
+		u.move(-1, rows)
+	case tcell.KeyDown:
+		u.move(1, rows)
+	case tcell.KeyRight:
+		if row.field == nil {
+			u.expanded[row.section] = true
+		}
+	case tcell.KeyLeft:
+		if row.field == nil {
+			u.expanded[row.section] = false
+		} else {
+			u.cursor = u.sectionRow(row.section)
+		}
+	case tcell.KeyPgUp:
+		u.scrollPreview(-10)
+	case tcell.KeyPgDn:
+		u.scrollPreview(10)
+	case tcell.KeyEnter:
+		u.activate(row)
+	case tcell.KeyCtrlS:
+		return u.save()
+	case tcell.KeyCtrlC:
+		return true, false
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'k':
+			u.move(-1, rows)
+		case 'j':
+			u.move(1, rows)
+		case ' ':
+			u.activate(row)
+		case 'd':
+			if row.field != nil {
+				u.resetField(row.field)
+			}
+		case 's':
+			return u.save()
+		case 'q':
+			if u.quitting {
+				return true, false
+			}
+			u.quitting = true
+			u.setMessage("Press q again to quit without saving", true)
+		}
+	}
+	return false, false
+}
+
+// Moves the cursor and scrolls the preview to the section under it
+func (u *tui) move(delta int, rows []tuiRow) {
+	u.cursor += delta
+	if u.cursor < 0 {
+		u.cursor = 0
+	}
+	if u.cursor >= len(rows) {
+		u.cursor = len(rows) - 1
+	}
+	u.previewSection(rows[u.cursor].section)
+}
+
+// Scrolls the preview pane by the number of lines
+func (u *tui) scrollPreview(delta int) {
+	u.previewTop += delta
+	if u.previewTop >= len(u.preview) {
+		u.previewTop = len(u.preview) - 1
+	}
+	if u.previewTop < 0 {
+		u.previewTop = 0
+	}
+}
+
+// Returns the row of the section header
+func (u *tui) sectionRow(section int) int {
+	for i, r := range u.rows() {
+		if r.section == section && r.field == nil {
+			return i
+		}
+	}
+	return 0
+}
+
+// Expands or collapses a section, toggles a value or starts to edit it
+func (u *tui) activate(row tuiRow) {
+	if row.field == nil {
+		u.expanded[row.section] = !u.expanded[row.section]
+		return
+	}
+	if row.field.toggles(&u.t) {
+		row.field.toggle(&u.t)
+		u.refreshPreview()
+		return
+	}
+	u.editing = true
+	u.input = []rune(row.field.text(&u.t))
+	if row.field.secret {
+		u.setMessage("enter: set, no input keeps the current value  esc: cancel", false)
+		return
+	}
+	u.setMessage("enter: set  esc: cancel  ctrl-u: clear", false)
+}
+
+// Sets the value back to its default
+func (u *tui) resetField(f *tuiField) {
+	switch p := f.field(&u.t).(type) {
+	case *string:
+		*p = *f.field(&u.defaults).(*string)
+	case *bool:
+		*p = *f.field(&u.defaults).(*bool)
+	case *int:
+		*p = *f.field(&u.defaults).(*int)
+	case *[]string:
+		*p = *f.field(&u.defaults).(*[]string)
+	case *map[string]string:
+		*p = *f.field(&u.defaults).(*map[string]string)
+	}
+	u.setMessage(f.label+" set to the default", false)
+	u.refreshPreview()
+}
+
+// Leaves the UI when the values are valid, otherwise the first problem is shown
+func (u *tui) save() (bool, bool) {
+	errs := validateTemplate(u.t)
+	if len(errs) == 0 {
+		return true, true
+	}
+	u.setMessage(fmt.Sprintf("Found %d problem(s), %v", len(errs), errs[0].Error()), true)
+	return false, false
+}
+
+func (u *tui) setMessage(message string, isError bool) {
+	u.message = message
+	u.isError = isError
+}
+
+func (u *tui) draw() {
+	u.screen.Clear()
+	width, height := u.screen.Size()
+	split := width / 2
+	u.print(0, 0, width, " cnvrg.io values", styleTitle)
+	u.print(split+1, 0, width, "Preview of the values file", styleTitle)
+
+	// The section tree
+	rows := u.rows()
+	lines := height - 2
+	if u.cursor < u.top {
+		u.top = u.cursor
+	}
+	if u.cursor >= u.top+lines {
+		u.top = u.cursor - lines + 1
+	}
+	for i := 0; i < lines && u.top+i < len(rows); i++ {
+		row := rows[u.top+i]
+		y := i + 1
+		selected := u.top+i == u.cursor
+		if row.field == nil {
+			marker := "▸ "
+			if u.expanded[row.section] {
+				marker = "▾ "
+			}
+			style := styleSection
+			if selected {
+				style = style.Reverse(true)
+			}
+			u.print(1, y, split, marker+tuiSections[row.section].name, style)
+			continue
+		}
+		style := styleValue
+		if selected {
+			style = styleSelected
+		}
+		x := u.print(3, y, split, row.field.label+": ", style)
+		if selected && u.editing {
+			input := string(u.input)
+			if row.field.secret {
+				input = strings.Repeat("*", len(u.input))
+			}
+			if u.confirming {
+				input = "(confirm) " + input
+			}
+			u.print(x, y, split, input+"█", styleValue)
+			continue
+		}
+		value := row.field.display(&u.t)
+		if row.field.options != nil && value == "" {
+			value = "(none)"
+		}
+		x = u.print(x, y, split, value, style)
+		if def := row.field.display(&u.defaults); def != row.field.display(&u.t) && def != "" {
+			u.print(x+2, y, split, "(default: "+def+")", styleDefault)
+		}
+	}
+
+	// The preview pane
+	for y := 1; y < height-1; y++ {
+		u.screen.SetContent(split, y, '│', nil, styleDefault)
+	}
+	for i := 0; i < lines && u.previewTop+i < len(u.preview); i++ {
+		u.print(split+2, i+1, width, u.preview[u.previewTop+i], styleValue)
+	}
+
+	// The status line
+	style := styleDefault
+	if u.isError {
+		style = styleError
+	}
+	u.print(1, height-1, width, u.message, style)
+	u.screen.Show()
+}
+
+// Prints the text from x up to the limit and returns where it ended
+func (u *tui) print(x int, y int, limit int, text string, style tcell.Style) int {
+	for _, r := range text {
+		if x >= limit {
+			break
+		}
+		u.screen.SetContent(x, y, r, nil, style)
+		x++
+	}
+	return x
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Returns the UI on a simulated screen with every section expanded
+func newTestTui(t *testing.T) (*tui, tcell.SimulationScreen) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(screen.Fini)
+	screen.SetSize(160, 60)
+	u := newTui(screen, defaultTemplate())
+	for i := range u.expanded {
+		u.expanded[i] = true
+	}
+	return u, screen
+}
+
+// Moves the cursor down to the field with the label
+func selectField(t *testing.T, u *tui, label string) {
+	for i, row := range u.rows() {
+		if row.field != nil && row.field.label == label {
+			pressKeys(t, u, strings.Repeat("↓", i-u.cursor))
+			return
+		}
+	}
+	t.Fatalf("no field %q", label)
+}
+
+// Presses the keys one at a time and draws the screen after each, ↓ is the down key,
+// ⏎ is enter, ⌫ is backspace and ⌧ clears the input
+func pressKeys(t *testing.T, u *tui, keys string) {
+	for _, r := range keys {
+		var ev *tcell.EventKey
+		switch r {
+		case '↓':
+			ev = tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case '⏎':
+			ev = tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+		case '⌫':
+			ev = tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone)
+		case '⌧':
+			ev = tcell.NewEventKey(tcell.KeyCtrlU, 0, tcell.ModNone)
+		default:
+			ev = tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+		}
+		if done, _ := u.handleKey(ev); done {
+			t.Fatalf("the UI was left after %q", r)
+		}
+		u.draw()
+	}
+}
+
+// Returns the text of the screen, one line per row
+func screenText(screen tcell.SimulationScreen) string {
+	cells, width, height := screen.GetContents()
+	var lines []string
+	for y := 0; y < height; y++ {
+		var line []rune
+		for x := 0; x < width; x++ {
+			if runes := cells[y*width+x].Runes; len(runes) > 0 {
+				line = append(line, runes[0])
+			}
+		}
+		lines = append(lines, strings.TrimRight(string(line), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// A typed value is set, shown in the tree and preview, and a value which fails its check is kept
+func TestTuiEditField(t *testing.T) {
+	u, screen := newTestTui(t)
+	selectField(t, u, "Cluster Domain")
+	pressKeys(t, u, "⏎⌧example.com⏎")
+	if u.editing {
+		t.Fatal("still editing after enter")
+	}
+	if u.t.ClusterDomain.ClusterDomain != "example.com" {
+		t.Errorf("cluster domain = %q, want example.com", u.t.ClusterDomain.ClusterDomain)
+	}
+	text := screenText(screen)
+	if !strings.Contains(text, "Cluster Domain: example.com") {
+		t.Errorf("the tree does not show the value:\n%v", text)
+	}
+	if !strings.Contains(text, "clusterDomain: example.com") {
+		t.Errorf("the preview does not show the value:\n%v", text)
+	}
+
+	selectField(t, u, "Postgres Storage Size")
+	old := u.t.Dbs.PgStorageSize
+	pressKeys(t, u, "⏎⌧80xx⏎")
+	if !u.editing || !u.isError {
+		t.Errorf("an invalid size was accepted, editing %v, error %v", u.editing, u.isError)
+	}
+	if u.t.Dbs.PgStorageSize != old {
+		t.Errorf("postgres storage size = %q, want %q", u.t.Dbs.PgStorageSize, old)
+	}
+	pressKeys(t, u, "⌫⌫Gi⏎")
+	if u.editing || u.t.Dbs.PgStorageSize != "80Gi" {
+		t.Errorf("postgres storage size = %q, editing %v, want 80Gi", u.t.Dbs.PgStorageSize, u.editing)
+	}
+}
+
+// The d key sets a typed and a toggled value back to the default
+func TestTuiResetField(t *testing.T) {
+	u, screen := newTestTui(t)
+	defaults := defaultTemplate()
+
+	selectField(t, u, "HostPath Path")
+	pressKeys(t, u, "⏎⌧/data/cnvrg⏎")
+	if u.t.Storage.Hostpath.Path == defaults.Storage.Hostpath.Path {
+		t.Fatal("the hostpath path was not changed")
+	}
+	if text := screenText(screen); !strings.Contains(text, "(default: "+defaults.Storage.Hostpath.Path+")") {
+		t.Errorf("the tree does not show the default:\n%v", text)
+	}
+	pressKeys(t, u, "d")
+	if u.t.Storage.Hostpath.Path != defaults.Storage.Hostpath.Path {
+		t.Errorf("hostpath path = %q, want the default %q", u.t.Storage.Hostpath.Path, defaults.Storage.Hostpath.Path)
+	}
+	if u.message != "HostPath Path set to the default" {
+		t.Errorf("message = %q", u.message)
+	}
+
+	selectField(t, u, "Postgres")
+	pressKeys(t, u, " ")
+	if u.t.Dbs.PgEnable == defaults.Dbs.PgEnable {
+		t.Fatal("postgres was not toggled")
+	}
+	pressKeys(t, u, "d")
+	if u.t.Dbs.PgEnable != defaults.Dbs.PgEnable {
+		t.Errorf("postgres = %v, want the default %v", u.t.Dbs.PgEnable, defaults.Dbs.PgEnable)
+	}
+}
+
+// A password is set only when it is typed twice the same, and is never shown
+func TestTuiSecretConfirmation(t *testing.T) {
+	u, screen := newTestTui(t)
+	selectField(t, u, "Password")
+
+	pressKeys(t, u, "⏎s3cret⏎")
+	if !u.confirming || u.t.Registry.Password != "" {
+		t.Fatalf("the password was set before it was confirmed, confirming %v", u.confirming)
+	}
+	pressKeys(t, u, "s3cret")
+	text := screenText(screen)
+	if !strings.Contains(text, "Password: (confirm) ******") {
+		t.Errorf("the confirmation is not masked:\n%v", text)
+	}
+	pressKeys(t, u, "⏎")
+	if u.editing || u.t.Registry.Password != "s3cret" {
+		t.Fatalf("password = %q, editing %v, want s3cret", u.t.Registry.Password, u.editing)
+	}
+	if text := screenText(screen); strings.Contains(text, "s3cret") {
+		t.Errorf("the password is shown:\n%v", text)
+	}
+
+	// The values do not match, the password is kept
+	pressKeys(t, u, "⏎other⏎othr⏎")
+	if !u.editing || u.confirming || !u.isError {
+		t.Errorf("a mismatch was accepted, editing %v, confirming %v", u.editing, u.confirming)
+	}
+	if u.t.Registry.Password != "s3cret" {
+		t.Errorf("password = %q after a mismatch, want s3cret", u.t.Registry.Password)
+	}
+
+	// No input keeps the current password without a confirmation
+	pressKeys(t, u, "⏎")
+	if u.editing || u.t.Registry.Password != "s3cret" {
+		t.Errorf("password = %q, editing %v after no input, want s3cret", u.t.Registry.Password, u.editing)
+	}
+}
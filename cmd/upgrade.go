@@ -0,0 +1,296 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Set by the flags of the upgrade command
+var (
+	previousValues string
+	skipConfirm    bool
+)
+
+// The sections of the Advanced Options menu and the values they hold,
+// used to group the changes shown before an upgrade
+var valuesSections = []struct {
+	name string
+	keys []string
+}{
+	{"General", []string{"clusterDomain", "imageHub"}},
+	{"Labeling", []string{"labels", "annotations", "clusterInternalDomain"}},
+	{"Networking", []string{"networking"}},
+	{"Logging", []string{"logging"}},
+	{"Registry", []string{"registry"}},
+	{"Tenancy", []string{"tenancy"}},
+	{"Single Sign On", []string{"sso"}},
+	{"Storage", []string{"storage"}},
+	{"Miscellaneous", []string{"backup", "gpu", "configReloader", "capsule"}},
+	{"Monitoring", []string{"monitoring"}},
+	{"Control Plane", []string{"controlPlane"}},
+	{"Databases", []string{"dbs"}},
+}
+
+// A value which differs between the deployed and the new values
+type valueChange struct {
+	Path string
+	Old  string
+	New  string
+}
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade cnvrg.io after reviewing the changes to the values",
+	Long: `Compare the values file to the values of the deployed release, or to a
+previous values file, show the changes grouped by section and run
+'helm upgrade' once they are confirmed, for example:
+
+  cnvrg-deploy-cli upgrade --values values.yaml --namespace cnvrg
+  cnvrg-deploy-cli upgrade --values values.yaml --previous old-values.yaml
+
+The release keeps the version of the cnvrg chart it is deployed with,
+unless another version is set with --version.
+
+The helm binary must be installed.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		InfoLogger.Println("In the upgrade command")
+		t, err := readValuesFile(installValuesFiles()...)
+		if err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
+		if errs := validateTemplate(t); len(errs) > 0 {
+			printValidationErrors(errs)
+			return fmt.Errorf("%v is not valid, nothing was upgraded", installValues)
+		}
+
+		newValues, err := readMergedValues(installValuesFiles()...)
+		if err != nil {
+			return err
+		}
+		oldValues, err := deployedValues()
+		if err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
+		changes, err := diffValues(oldValues, newValues)
+		if err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
+		printChanges(changes)
+		if len(changes) == 0 && installVersion == "" {
+			fmt.Println((colorGreen), "Nothing to upgrade")
+			return nil
+		}
+
+		if !skipConfirm {
+			if !promptConfirm("Upgrade the release with these changes? [y/N]: ") {
+				fmt.Println((colorYellow), "The upgrade was cancelled")
+				return nil
+			}
+		}
+		if err := addHelmRepo(); err != nil {
+			return err
+		}
+		// helm upgrade moves to the latest chart without --version, keep the deployed one
+		if installVersion == "" {
+			version, err := deployedChartVersion()
+			if err != nil {
+				ErrorLogger.Println(err)
+				return err
+			}
+			fmt.Printf("%v Keeping the deployed version %v of the cnvrg chart\n", colorWhite, version)
+			installVersion = version
+		}
+		return runHelmRelease("upgrade", installValuesFiles()...)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+
+	upgradeCmd.Flags().StringVar(&installValues, "values", "values.yaml", "new values file to upgrade cnvrg.io with")
+	upgradeCmd.Flags().StringVar(&installSecrets, "secrets-file", "", "secrets file written by 'create values --secrets file', merged into the values")
+	upgradeCmd.Flags().StringVar(&previousValues, "previous", "", "previous values file to compare with instead of the deployed release")
+	upgradeCmd.Flags().StringVarP(&installNamespace, "namespace", "n", "cnvrg", "namespace cnvrg.io is installed in")
+	upgradeCmd.Flags().StringVar(&installVersion, "version", "", "version of the cnvrg chart, the version of the deployed release when empty")
+	upgradeCmd.Flags().StringVar(&installRelease, "release", "cnvrg", "name of the Helm release")
+	upgradeCmd.Flags().StringVar(&installTimeout, "timeout", "1500s", "time to wait for the upgrade to finish")
+	upgradeCmd.Flags().StringVar(&helmBinary, "helm", "helm", "helm binary used to upgrade cnvrg.io")
+	upgradeCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "upgrade without asking for confirmation")
+}
+
+// Returns the values of the previous values file, or of the deployed release
+func deployedValues() ([]byte, error) {
+	if previousValues != "" {
+		InfoLogger.Printf("Comparing with the previous values file %v\n", previousValues)
+		return os.ReadFile(previousValues)
+	}
+	InfoLogger.Printf("Getting the values of the %v release\n", installRelease)
+	out, err := exec.Command(helmBinary, "get", "values", installRelease, "-n", installNamespace, "-o", "yaml").Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("unable to get the values of the %v release: %v", installRelease, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("unable to get the values of the %v release: %w", installRelease, err)
+	}
+	return out, nil
+}
+
+// A release in the output of helm list
+type helmRelease struct {
+	Name  string `json:"name"`
+	Chart string `json:"chart"`
+}
+
+// Returns the version of the cnvrg chart the release is deployed with
+func deployedChartVersion() (string, error) {
+	InfoLogger.Printf("Getting the chart version of the %v release\n", installRelease)
+	out, err := exec.Command(helmBinary, "list", "-n", installNamespace, "--filter", "^"+regexp.QuoteMeta(installRelease)+"$", "-o", "json").Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("unable to get the chart version of the %v release: %v", installRelease, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("unable to get the chart version of the %v release: %w", installRelease, err)
+	}
+	var releases []helmRelease
+	if err := json.Unmarshal(out, &releases); err != nil {
+		return "", fmt.Errorf("unable to parse the releases listed by helm: %w", err)
+	}
+	for _, r := range releases {
+		if r.Name != installRelease {
+			continue
+		}
+		// The chart is listed as <name>-<version>
+		chart := strings.TrimPrefix(helmChart, helmRepoName+"/") + "-"
+		if !strings.HasPrefix(r.Chart, chart) {
+			return "", fmt.Errorf("the %v release is deployed with the %v chart, not the cnvrg chart", installRelease, r.Chart)
+		}
+		return strings.TrimPrefix(r.Chart, chart), nil
+	}
+	return "", fmt.Errorf("the %v release was not found in the %v namespace, set the chart version with --version", installRelease, installNamespace)
+}
+
+// Returns every value which was added, removed or changed, sorted by path
+func diffValues(oldData []byte, newData []byte) ([]valueChange, error) {
+	var oldMap, newMap map[string]interface{}
+	if err := yaml.Unmarshal(oldData, &oldMap); err != nil {
+		return nil, fmt.Errorf("unable to parse the deployed values: %w", err)
+	}
+	if err := yaml.Unmarshal(newData, &newMap); err != nil {
+		return nil, fmt.Errorf("unable to parse the new values: %w", err)
+	}
+	oldFlat := map[string]string{}
+	newFlat := map[string]string{}
+	flattenValues("", oldMap, oldFlat)
+	flattenValues("", newMap, newFlat)
+
+	var changes []valueChange
+	for path, value := range newFlat {
+		if old, ok := oldFlat[path]; !ok || old != value {
+			changes = append(changes, valueChange{Path: path, Old: oldFlat[path], New: value})
+		}
+	}
+	for path, value := range oldFlat {
+		if _, ok := newFlat[path]; !ok {
+			changes = append(changes, valueChange{Path: path, Old: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// Flattens the nested values into dotted paths, lists and scalars are kept as YAML
+func flattenValues(prefix string, values map[string]interface{}, flat map[string]string) {
+	for key, value := range values {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if m, ok := value.(map[string]interface{}); ok && len(m) > 0 {
+			flattenValues(path, m, flat)
+			continue
+		}
+		s, err := toYaml(value)
+		if err != nil {
+			s = fmt.Sprint(value)
+		}
+		flat[path] = s
+	}
+}
+
+// Returns the name of the section the value belongs to
+func valuesSection(path string) string {
+	top := strings.SplitN(path, ".", 2)[0]
+	for _, s := range valuesSections {
+		for _, key := range s.keys {
+			if key == top {
+				return s.name
+			}
+		}
+	}
+	return "Other"
+}
+
+// Prints the changes grouped by the sections of the Advanced Options menu
+func printChanges(changes []valueChange) {
+	if len(changes) == 0 {
+		fmt.Println((colorGreen), "The values have not changed")
+		return
+	}
+	fmt.Printf("%v Found %d change(s) to the values:\n", colorGreen, len(changes))
+	grouped := map[string][]valueChange{}
+	for _, c := range changes {
+		section := valuesSection(c.Path)
+		grouped[section] = append(grouped[section], c)
+	}
+	names := []string{}
+	for _, s := range valuesSections {
+		names = append(names, s.name)
+	}
+	names = append(names, "Other")
+	for _, name := range names {
+		if len(grouped[name]) == 0 {
+			continue
+		}
+		fmt.Println()
+		fmt.Printf("%v ---------%v---------\n", colorBlue, name)
+		for _, c := range grouped[name] {
+			if isSecretPath(c.Path) {
+				c.Old, c.New = maskChange(c.Old), maskChange(c.New)
+			}
+			switch {
+			case c.Old == "":
+				fmt.Printf("%v   + %v: %v\n", colorGreen, c.Path, c.New)
+			case c.New == "":
+				fmt.Printf("%v   - %v: %v\n", colorYellow, c.Path, c.Old)
+			default:
+				fmt.Printf("%v   ~ %v: %v -> %v\n", colorWhite, c.Path, c.Old, c.New)
+			}
+		}
+	}
+	fmt.Println()
+}
+
+// Masks a secret value in a change, keeping it empty when it was added or removed
+func maskChange(value string) string {
+	if value == "" {
+		return ""
+	}
+	return maskedValue
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Without --version the upgrade keeps the chart version of the deployed release
+func TestUpgradeKeepsDeployedVersion(t *testing.T) {
+	log := setupFakeHelm(t)
+	setupInstall(t)
+	installVersion = ""
+	defer func(skip bool) { skipConfirm = skip }(skipConfirm)
+	skipConfirm = true
+	t.Setenv("FAKE_HELM_LIST", `[{"name":"cnvrg","namespace":"cnvrg-test","chart":"cnvrg-4.7.33","app_version":"4.7.33"}]`)
+
+	if err := upgradeCmd.RunE(upgradeCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	calls := helmCalls(t, log)
+	want := []string{"upgrade", "cnvrg", "cnvrgv3/cnvrg", "-n", "cnvrg-test", "--timeout", "600s", "--wait",
+		"--values", installValues, "--version", "4.7.33"}
+	if last := calls[len(calls)-1]; !reflect.DeepEqual(last, want) {
+		t.Errorf("helm was called with\n%q\nwant\n%q", last, want)
+	}
+}
+
+// The upgrade stops when the version of the deployed release is unknown
+func TestUpgradeWithoutDeployedRelease(t *testing.T) {
+	log := setupFakeHelm(t)
+	setupInstall(t)
+	installVersion = ""
+	defer func(skip bool) { skipConfirm = skip }(skipConfirm)
+	skipConfirm = true
+	t.Setenv("FAKE_HELM_LIST", `[]`)
+
+	if err := upgradeCmd.RunE(upgradeCmd, nil); err == nil {
+		t.Error("the upgrade succeeded without a deployed release")
+	}
+	for _, call := range helmCalls(t, log) {
+		if call[0] == "upgrade" {
+			t.Errorf("helm upgrade was called without a chart version: %q", call)
+		}
+	}
+}
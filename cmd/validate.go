@@ -0,0 +1,202 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// A problem found in the values, the field is the path of the value in the values file
+type validationError struct {
+	Field   string
+	Message string
+}
+
+func (e validationError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Field, e.Message)
+}
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate [values.yaml]",
+	Short: "Validate a values file before installing cnvrg.io",
+	Long: `Check a values file for missing required values and settings which
+conflict with each other. Every problem found is reported with the path
+of the value in the values file, for example:
+
+  cnvrg-deploy-cli validate values.yaml
+  cnvrg-deploy-cli validate values.yaml --secrets-file secrets.yaml`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := "values.yaml"
+		if len(args) == 1 {
+			name = args[0]
+		}
+		files := []string{name}
+		if validateSecrets != "" {
+			files = append(files, validateSecrets)
+		}
+		t, err := readValuesFile(files...)
+		if err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
+		if errs := validateTemplate(t); len(errs) > 0 {
+			printValidationErrors(errs)
+			return fmt.Errorf("%v is not valid", name)
+		}
+		fmt.Printf("%v %v is valid\n", colorGreen, name)
+
+		if testSmtpServer {
+			if err := testSmtp(t.ControlPlane); err != nil {
+				ErrorLogger.Println(err)
+				return err
+			}
+			fmt.Printf("%v Connected to the SMTP server %v\n", colorGreen, t.ControlPlane.SmtpServer)
+		}
+		if testObjectStorage {
+			if err := probeObjectStorage(t.ControlPlane); err != nil {
+				ErrorLogger.Println(err)
+				return err
+			}
+			fmt.Printf("%v Connected to the object storage bucket %v\n", colorGreen, t.ControlPlane.ObjectStorageBucket)
+		}
+		return nil
+	},
+}
+
+// Set by the flags of the validate command
+var (
+	testSmtpServer    bool
+	testObjectStorage bool
+	validateSecrets   string
+)
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().StringVar(&validateSecrets, "secrets-file", "", "secrets file written by 'create values --secrets file', merged into the values")
+	validateCmd.Flags().StringVar(&catalogSource, "catalog", "", "file or URL of the release catalog the image tags are checked against, the embedded catalog when empty")
+	validateCmd.Flags().BoolVar(&testSmtpServer, "test-smtp", false, "connect to the SMTP server of the values file")
+	validateCmd.Flags().BoolVar(&testObjectStorage, "test-object-storage", false, "connect to the S3 compatible object storage bucket of the values file")
+}
+
+// Checks the Template for missing required values and conflicting
+// settings and returns every problem found
+func validateTemplate(t Template) []validationError {
+	var errs []validationError
+	add := func(field string, format string, a ...interface{}) {
+		errs = append(errs, validationError{Field: field, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if t.ClusterDomain.ClusterDomain == "" {
+		add("clusterDomain", "is required")
+	}
+
+	// Networking
+	switch t.Network.Ingress.Type {
+	case "", "istio", "ingress", "openshift", "nodeport":
+	default:
+		add("networking.ingress.type", "must be one of istio, ingress, openshift or nodeport, got %q", t.Network.Ingress.Type)
+	}
+	if !t.Network.Istio.Enabled && (t.Network.Ingress.Type == "" || t.Network.Ingress.Type == "istio") {
+		add("networking.ingress.type", "must be set to ingress, openshift or nodeport when istio is disabled")
+	}
+	if t.Network.Proxy.Enabled && len(t.Network.Proxy.HttpProxy) == 0 && len(t.Network.Proxy.HttpsProxy) == 0 {
+		add("networking.proxy", "requires httpProxy or httpsProxy when the proxy is enabled")
+	}
+
+	// Registry
+	if t.Registry.Enabled && t.Registry.Url == "" {
+		add("registry.url", "is required when the registry is enabled")
+	}
+	if t.Registry.Password != "" && t.Registry.User == "" {
+		add("registry.user", "is required when a registry password is set")
+	}
+
+	// Tenancy
+	if t.Tenancy.Enabled {
+		if t.Tenancy.Key == "" {
+			add("tenancy.key", "is required when tenancy is enabled")
+		}
+		if t.Tenancy.Value == "" {
+			add("tenancy.value", "is required when tenancy is enabled")
+		}
+	}
+
+	// Single Sign On
+	if t.Sso.Enabled {
+		if t.Sso.ClientId == "" {
+			add("sso.clientId", "is required when single sign on is enabled")
+		}
+		if t.Sso.ClientSecret == "" && secretsMode != secretsExisting {
+			add("sso.clientSecret", "is required when single sign on is enabled")
+		}
+	}
+
+	// Storage
+	if t.Storage.Nfs.Enabled {
+		if t.Storage.Nfs.Server == "" {
+			add("storage.nfs.server", "is required when NFS is enabled")
+		}
+		if t.Storage.Nfs.Path == "" {
+			add("storage.nfs.path", "is required when NFS is enabled")
+		}
+	}
+	validateReclaimPolicy(t.Storage.Nfs.ReclaimPolicy, "storage.nfs.reclaimPolicy", add)
+	validateReclaimPolicy(t.Storage.Hostpath.ReclaimPolicy, "storage.hostpath.reclaimPolicy", add)
+
+	// Miscellaneous
+	if t.Backup.Rotation < 0 {
+		add("backup.rotation", "must not be negative, got %d", t.Backup.Rotation)
+	}
+
+	// Control Plane
+	cp := t.ControlPlane
+	if smtpConfigured(cp) && cp.SmtpServer == "" {
+		add("controlPlane.smtp.server", "is required when SMTP is configured")
+	}
+	if cp.SmtpPort != 0 && !validSmtpPort(cp.SmtpPort) {
+		add("controlPlane.smtp.port", "must be between 1 and 65535, got %d", cp.SmtpPort)
+	}
+	if cp.SmtpOpenSslMode != "" && !validSmtpVerifyMode(cp.SmtpOpenSslMode) {
+		add("controlPlane.smtp.opensslVerifyMode", "must be one of %v, got %q", strings.Join(smtpVerifyModes, ", "), cp.SmtpOpenSslMode)
+	}
+	if cp.SmtpSender != "" && !validEmail(cp.SmtpSender) {
+		add("controlPlane.smtp.sender", "must be an email address, got %q", cp.SmtpSender)
+	}
+	if cp.MpiRegistryPassword != "" && cp.MpiRegistryUser == "" {
+		add("controlPlane.mpi.registry.user", "is required when an MPI registry password is set")
+	}
+	if (cp.MpiRegistryUser != "" || cp.MpiRegistryPassword != "") && cp.MpiRegistryUrl == "" {
+		add("controlPlane.mpi.registry.url", "is required when MPI registry credentials are set")
+	}
+	validateObjectStorage(cp, add)
+	validateScaling(cp, add)
+	validateRelease(cp, add)
+
+	return errs
+}
+
+// Adds an error when the reclaim policy is set to anything but Retain, Delete or Recycle
+func validateReclaimPolicy(policy string, field string, add func(string, string, ...interface{})) {
+	switch policy {
+	case "", "Retain", "Delete", "Recycle":
+	default:
+		add(field, "must be one of Retain, Delete or Recycle, got %q", policy)
+	}
+}
+
+// Prints every validation error
+func printValidationErrors(errs []validationError) {
+	fmt.Printf("%v Found %d problem(s) with the values:\n", colorYellow, len(errs))
+	for _, e := range errs {
+		fmt.Println((colorWhite), " ", e.Error())
+	}
+}
@@ -5,11 +5,8 @@ Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
 package cmd
 
 import (
-	"bufio"
 	"fmt"
-	"log"
 	"os"
-	"strconv"
 	"strings"
 	"text/template"
 
@@ -18,290 +15,341 @@ import (
 
 // Initalize all structs as global variables
 // set variables for each struct defined above - Used in gather functions for each menu item
-// This also sets any defaults needed for templating
+// The defaults needed for templating are set in defaultTemplate
 var (
-	clusterdomain  = ClusterDomain{}
-	internalDomain = ClusterInteralDomain{Domain: "cluster.local"}
-	labels         = Labels{}
-	annotations    = Annotations{}
-	network        = Networking{Istio: Istio{Enabled: true}, Ingress: Ingress{IstioGwEnabled: true}}
-	logging        = Logging{FluentbitEnable: true, ElastalertEnable: true, KibanaEnable: true}
-	registry       = Registry{}
-	tenancy        = Tenancy{}
-	sso            = Sso{}
-	storage        = Storage{Hostpath: Hostpath{Path: "/cnvrg-hostpath-storage"}}
-	gpu            = Gpu{NvidiaEnable: true, HabanaEnable: true}
-	backup         = Backup{Enabled: true}
-	capsule        = Capsule{Enabled: true}
-	configreloader = ConfigReloader{Enabled: true}
-	monitoring     = Monitoring{DcgmExportEnable: true, HabanaExportEnable: true, NodeExportEnable: true, KubeStateMetricEnable: true,
-		GrafanaEnable: true, PrometheusOperatorEnable: true, PrometheusEnable: true, DefaultSvcMonitorsEnable: true, CnvrgIdleMetricsEnable: true}
-	controlplane = ControlPlane{HyperEnable: true, CnvrgScheduleEnable: true, SearchkiqEnable: true, SidekiqEnable: true, SystemkiqEnable: true,
-		WebappEnable: true, MpiEnable: true, SearchkiqHpaEnable: true, SidekiqHpaEnable: true, SystemkiqHpaEnable: true, WebappHpaEnable: true}
-	dbs = Dbs{EsEnable: true, MinioEnable: true, PgEnable: true, RedisEnable: true}
+	clusterdomain  ClusterDomain
+	internalDomain ClusterInteralDomain
+	labels         Labels
+	annotations    Annotations
+	network        Networking
+	logging        Logging
+	registry       Registry
+	tenancy        Tenancy
+	sso            Sso
+	storage        Storage
+	gpu            Gpu
+	backup         Backup
+	capsule        Capsule
+	configreloader ConfigReloader
+	monitoring     Monitoring
+	controlplane   ControlPlane
+	dbs            Dbs
 )
 
 // Global Variables
 var (
+	// The embedded values.tmpl, or the custom template set with --template
 	temp *template.Template
 
+	// Set by the flags of the values command
+	valuesFile  string
+	answersFile string
+	fromFile    string
+
 	// Set colors for text
 	colorBlue   = "\033[34m"
 	colorWhite  = "\033[37m"
 	colorYellow = "\033[33m"
 	colorGreen  = "\033[32m"
-
-	// Set variables for error handling
-	WarningLogger *log.Logger
-	InfoLogger    *log.Logger
-	ErrorLogger   *log.Logger
 )
 
 func init() {
 	createCmd.AddCommand(valuesCmd)
-	temp = template.Must(template.ParseFiles("values.tmpl"))
-	// Create and configure a log.txt file to capture all errors and logs
-	file, error := os.OpenFile("logs.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
-	if error != nil {
-		log.Fatal(error)
-	}
-	log.SetOutput(file)
-
-	InfoLogger = log.New(file, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
-	WarningLogger = log.New(file, "WARNING: ", log.Ldate|log.Ltime|log.Lshortfile)
-	ErrorLogger = log.New(file, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
+	applyTemplate(defaultTemplate())
+	valuesCmd.Flags().StringVarP(&valuesFile, "output", "o", "values.yaml", "name of the values file to generate")
+	valuesCmd.Flags().StringVar(&answersFile, "answers", "", "answers file used to generate the values file without prompts")
+	valuesCmd.Flags().StringVar(&fromFile, "from", "", "existing values file to load and edit in the menus")
+	valuesCmd.Flags().StringVar(&secretsMode, "secrets", secretsInline, "how secret values are written, Secret manifests, references to existing Secrets, a separate values file or the values file [manifest|existing|file|inline]")
+	valuesCmd.Flags().StringVar(&secretsFile, "secrets-file", "secrets.yaml", "name of the secrets file written in the manifest and file secrets modes")
+	valuesCmd.Flags().StringVar(&profileName, "profile", "", "profile to preset the values with [aws-eks|on-prem-nfs|air-gapped|minimal-no-monitoring|openshift], air-gapped needs --registry-url and on-prem-nfs --nfs-server and --nfs-path")
+	valuesCmd.Flags().StringVar(&releaseName, "release", "", "cnvrg.io release to take the image tags from, see 'versions'")
+	valuesCmd.Flags().StringVar(&catalogSource, "catalog", "", "file or URL of the release catalog, the embedded catalog when empty")
+	valuesCmd.Flags().StringVar(&profileDir, "profile-dir", defaultProfileDir(), "directory of user profiles")
+	addFieldFlags(valuesCmd)
+	valuesCmd.Flags().StringVar(&recordFile, "record", "", "file to record the answers of the menus to")
+	valuesCmd.Flags().StringVar(&replayFile, "replay", "", "session recorded with --record to answer the menus with")
+	valuesCmd.Flags().BoolVar(&tuiMode, "tui", false, "edit the values in a full screen UI with a live preview of the values file")
+	valuesCmd.Flags().BoolVar(&testSmtpServer, "test-smtp", false, "connect to the SMTP server before the values file is written")
+	valuesCmd.Flags().StringVar(&templateFile, "template", "", "custom template to render the values file with, the embedded values.tmpl when empty")
+	temp = template.Must(parseValuesTemplate(""))
 }
 
 // Parent struct for the Backup values
 type Backup struct {
-	Enabled  bool
-	Rotation int
-	Period   string
+	Enabled  bool   `yaml:"enabled"`
+	Rotation int    `yaml:"rotation"`
+	Period   string `yaml:"period"`
 }
 
 type Gpu struct {
-	NvidiaEnable bool
-	HabanaEnable bool
+	NvidiaEnable bool `yaml:"nvidiaEnable"`
+	HabanaEnable bool `yaml:"habanaEnable"`
 }
 
 type Dbs struct {
-	CvatEnable bool
-
-	EsEnable         bool
-	EsStorageSize    string
-	EsStorageClass   string
-	EsPatchNodes     bool
-	EsNodeSelector   string
-	CleanUpAll       string
-	CleanUpApp       string
-	CleanUpJobs      string
-	CleanUpEndpoints string
-
-	MinioEnable       bool
-	MinioStorageSize  string
-	MinioStorageClass string
-	MinioNodeSelector string
-
-	PgEnable       bool
-	PgStorageSize  string
-	PgStorageClass string
-	PgNodeSelector string
-	PgPagesEnable  bool
-	PgPagesSize    string
-	PgPagesMemory  string
-
-	RedisEnable       bool
-	RedisStorageSize  string
-	RedisStorageClass string
-	RedisNodeSelector string
+	CvatEnable bool `yaml:"cvatEnable"`
+
+	EsEnable         bool              `yaml:"esEnable"`
+	EsStorageSize    string            `yaml:"esStorageSize"`
+	EsStorageClass   string            `yaml:"esStorageClass"`
+	EsPatchNodes     bool              `yaml:"esPatchNodes"`
+	EsNodeSelector   map[string]string `yaml:"esNodeSelector"`
+	CleanUpAll       string            `yaml:"cleanUpAll"`
+	CleanUpApp       string            `yaml:"cleanUpApp"`
+	CleanUpJobs      string            `yaml:"cleanUpJobs"`
+	CleanUpEndpoints string            `yaml:"cleanUpEndpoints"`
+
+	MinioEnable       bool              `yaml:"minioEnable"`
+	MinioStorageSize  string            `yaml:"minioStorageSize"`
+	MinioStorageClass string            `yaml:"minioStorageClass"`
+	MinioNodeSelector map[string]string `yaml:"minioNodeSelector"`
+
+	PgEnable       bool              `yaml:"pgEnable"`
+	PgStorageSize  string            `yaml:"pgStorageSize"`
+	PgStorageClass string            `yaml:"pgStorageClass"`
+	PgNodeSelector map[string]string `yaml:"pgNodeSelector"`
+	PgPagesEnable  bool              `yaml:"pgPagesEnable"`
+	PgPagesSize    string            `yaml:"pgPagesSize"`
+	PgPagesMemory  string            `yaml:"pgPagesMemory"`
+
+	RedisEnable       bool              `yaml:"redisEnable"`
+	RedisStorageSize  string            `yaml:"redisStorageSize"`
+	RedisStorageClass string            `yaml:"redisStorageClass"`
+	RedisNodeSelector map[string]string `yaml:"redisNodeSelector"`
 }
 
 type ControlPlane struct {
-	Image string
-
-	BaseConfigAgentTag        string
-	BaseConfigIntercom        bool
-	BaseConfigFeatureFlags    string
-	BaseConfigCnvrgPrivileged bool
-
-	HyperEnable bool
-
-	CnvrgScheduleEnable bool
-
-	CnvrgClusterProvisionerEnable bool
-
-	ObjectStorageType            string
-	ObjectStorageBucket          string
-	ObjectStorageRegion          string
-	ObjectStorageAccessKey       string
-	ObjectStorageSecretKey       string
-	ObjectStorageEndpoint        string
-	ObjectStorageAzureAcountName string
-	ObjectStorageAzureContainer  string
-	ObjectStorageGcpSecretRef    string
-	ObjectStorageGcpProject      string
-
-	SearchkiqEnable         bool
-	SearchkiqHpaEnable      bool
-	SearchkiqHpaMaxReplicas int
-
-	SidekiqEnable         bool
-	SidekiqSplit          bool
-	SidekiqHpaEnable      bool
-	SidekiqHpaMaxReplicas int
-
-	CnvrgRouterEnable bool
-	CnvrgRouterImage  string
-
-	SmtpServer      string
-	SmtpPort        int
-	SmtpUsername    string
-	SmtpPassword    string
-	SmtpDomain      string
-	SmtpOpenSslMode string
-	SmtpSender      string
-
-	SystemkiqEnable         bool
-	SystemkiqHpaEnable      bool
-	SystemkiqHpaMaxReplicas int
-
-	WebappEnable         bool
-	WebappSvcName        string
-	WebappReplicas       int
-	WebappHpaEnable      bool
-	WebappHpaMaxReplicas int
-
-	MpiEnable           bool
-	MpiImage            string
-	MpiKubectlImage     string
-	MpiExtraArgs        string
-	MpiRegistryUrl      string
-	MpiRegistryUser     string
-	MpiRegistryPassword string
+	Image string `yaml:"image"`
+
+	BaseConfigAgentTag        string            `yaml:"baseConfigAgentTag"`
+	BaseConfigIntercom        bool              `yaml:"baseConfigIntercom"`
+	BaseConfigFeatureFlags    map[string]string `yaml:"baseConfigFeatureFlags"`
+	BaseConfigCnvrgPrivileged bool              `yaml:"baseConfigCnvrgPrivileged"`
+
+	HyperEnable bool `yaml:"hyperEnable"`
+
+	CnvrgScheduleEnable bool `yaml:"cnvrgScheduleEnable"`
+
+	CnvrgClusterProvisionerEnable bool `yaml:"cnvrgClusterProvisionerEnable"`
+
+	ObjectStorageType            string `yaml:"objectStorageType"`
+	ObjectStorageBucket          string `yaml:"objectStorageBucket"`
+	ObjectStorageRegion          string `yaml:"objectStorageRegion"`
+	ObjectStorageAccessKey       string `yaml:"objectStorageAccessKey"`
+	ObjectStorageSecretKey       string `yaml:"objectStorageSecretKey"`
+	ObjectStorageEndpoint        string `yaml:"objectStorageEndpoint"`
+	ObjectStorageAzureAcountName string `yaml:"objectStorageAzureAcountName"`
+	ObjectStorageAzureContainer  string `yaml:"objectStorageAzureContainer"`
+	ObjectStorageGcpSecretRef    string `yaml:"objectStorageGcpSecretRef"`
+	ObjectStorageGcpProject      string `yaml:"objectStorageGcpProject"`
+
+	SearchkiqEnable         bool `yaml:"searchkiqEnable"`
+	SearchkiqHpaEnable      bool `yaml:"searchkiqHpaEnable"`
+	SearchkiqHpaMaxReplicas int  `yaml:"searchkiqHpaMaxReplicas"`
+
+	SidekiqEnable         bool `yaml:"sidekiqEnable"`
+	SidekiqSplit          bool `yaml:"sidekiqSplit"`
+	SidekiqHpaEnable      bool `yaml:"sidekiqHpaEnable"`
+	SidekiqHpaMaxReplicas int  `yaml:"sidekiqHpaMaxReplicas"`
+
+	CnvrgRouterEnable bool   `yaml:"cnvrgRouterEnable"`
+	CnvrgRouterImage  string `yaml:"cnvrgRouterImage"`
+
+	SmtpServer      string `yaml:"smtpServer"`
+	SmtpPort        int    `yaml:"smtpPort"`
+	SmtpUsername    string `yaml:"smtpUsername"`
+	SmtpPassword    string `yaml:"smtpPassword"`
+	SmtpDomain      string `yaml:"smtpDomain"`
+	SmtpOpenSslMode string `yaml:"smtpOpenSslMode"`
+	SmtpSender      string `yaml:"smtpSender"`
+
+	SystemkiqEnable         bool `yaml:"systemkiqEnable"`
+	SystemkiqHpaEnable      bool `yaml:"systemkiqHpaEnable"`
+	SystemkiqHpaMaxReplicas int  `yaml:"systemkiqHpaMaxReplicas"`
+
+	WebappEnable         bool   `yaml:"webappEnable"`
+	WebappSvcName        string `yaml:"webappSvcName"`
+	WebappReplicas       int    `yaml:"webappReplicas"`
+	WebappHpaEnable      bool   `yaml:"webappHpaEnable"`
+	WebappHpaMaxReplicas int    `yaml:"webappHpaMaxReplicas"`
+
+	MpiEnable           bool              `yaml:"mpiEnable"`
+	MpiImage            string            `yaml:"mpiImage"`
+	MpiKubectlImage     string            `yaml:"mpiKubectlImage"`
+	MpiExtraArgs        map[string]string `yaml:"mpiExtraArgs"`
+	MpiRegistryUrl      string            `yaml:"mpiRegistryUrl"`
+	MpiRegistryUser     string            `yaml:"mpiRegistryUser"`
+	MpiRegistryPassword string            `yaml:"mpiRegistryPassword"`
 }
 
 type Logging struct {
-	FluentbitEnable    bool
-	ElastalertEnable   bool
-	ElastaStorageSize  string
-	ElastaStorageClass string
-	ElastaNodeSelector string
-	KibanaEnable       bool
-	KibanaSvcName      string
+	FluentbitEnable    bool              `yaml:"fluentbitEnable"`
+	ElastalertEnable   bool              `yaml:"elastalertEnable"`
+	ElastaStorageSize  string            `yaml:"elastaStorageSize"`
+	ElastaStorageClass string            `yaml:"elastaStorageClass"`
+	ElastaNodeSelector map[string]string `yaml:"elastaNodeSelector"`
+	KibanaEnable       bool              `yaml:"kibanaEnable"`
+	KibanaSvcName      string            `yaml:"kibanaSvcName"`
 }
 
 //Parent struct for the Capsule values
 type Capsule struct {
-	Enabled bool
-	Image   string
+	Enabled bool   `yaml:"enabled"`
+	Image   string `yaml:"image"`
 }
 
 // Parent level of ConfigReloader struct
 type ConfigReloader struct {
-	Enabled bool
+	Enabled bool `yaml:"enabled"`
 }
 
 // Parent level of Registry struct
 type Registry struct {
-	User     string
-	Password string
-	Url      string
-	Enabled  bool
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Url      string `yaml:"url"`
+	Enabled  bool   `yaml:"enabled"`
 }
 
 //Parent level of Tenancy struct
 type Tenancy struct {
-	Enabled bool
-	Key     string
-	Value   string
+	Enabled bool   `yaml:"enabled"`
+	Key     string `yaml:"key"`
+	Value   string `yaml:"value"`
 }
 
 // Parent level of SSO struct
 type Sso struct {
-	Enabled       bool
-	AdminUser     string
-	Provider      string
-	EmailDomain   string
-	ClientId      string
-	ClientSecret  string
-	AzureTenant   string
-	OidcIssuerUrl string
+	Enabled       bool     `yaml:"enabled"`
+	AdminUser     string   `yaml:"adminUser"`
+	Provider      string   `yaml:"provider"`
+	EmailDomain   []string `yaml:"emailDomain"`
+	ClientId      string   `yaml:"clientId"`
+	ClientSecret  string   `yaml:"clientSecret"`
+	AzureTenant   string   `yaml:"azureTenant"`
+	OidcIssuerUrl string   `yaml:"oidcIssuerUrl"`
 }
 
 // Parent level of Storage struct
 type Storage struct {
-	Hostpath Hostpath
-	Nfs      Nfs
+	Hostpath Hostpath `yaml:"hostpath"`
+	Nfs      Nfs      `yaml:"nfs"`
 }
 
 // Used in the Storage struct
 type Hostpath struct {
-	Enabled       bool
-	DefaultSc     bool
-	Path          string
-	ReclaimPolicy string
-	NodeSelector  string
+	Enabled       bool              `yaml:"enabled"`
+	DefaultSc     bool              `yaml:"defaultSc"`
+	Path          string            `yaml:"path"`
+	ReclaimPolicy string            `yaml:"reclaimPolicy"`
+	NodeSelector  map[string]string `yaml:"nodeSelector"`
 }
 
 // Used in the Storage struct
 type Nfs struct {
-	Enabled       bool
-	Server        string
-	Path          string
-	DefaultSc     bool
-	ReclaimPolicy string
-	Image         string
+	Enabled       bool   `yaml:"enabled"`
+	Server        string `yaml:"server"`
+	Path          string `yaml:"path"`
+	DefaultSc     bool   `yaml:"defaultSc"`
+	ReclaimPolicy string `yaml:"reclaimPolicy"`
+	Image         string `yaml:"image"`
 }
 
 type Monitoring struct {
-	DcgmExportEnable         bool
-	HabanaExportEnable       bool
-	NodeExportEnable         bool
-	KubeStateMetricEnable    bool
-	GrafanaEnable            bool
-	GrafanaSvcName           string
-	PrometheusOperatorEnable bool
-	PrometheusEnable         bool
-	PrometheusStorageSize    string
-	PrometheusStorageClass   string
-	PrometheusNodeSelector   string
-	DefaultSvcMonitorsEnable bool
-	CnvrgIdleMetricsEnable   bool
-	CnvrgIdleMetricsLabels   string
+	DcgmExportEnable         bool              `yaml:"dcgmExportEnable"`
+	HabanaExportEnable       bool              `yaml:"habanaExportEnable"`
+	NodeExportEnable         bool              `yaml:"nodeExportEnable"`
+	KubeStateMetricEnable    bool              `yaml:"kubeStateMetricEnable"`
+	GrafanaEnable            bool              `yaml:"grafanaEnable"`
+	GrafanaSvcName           string            `yaml:"grafanaSvcName"`
+	PrometheusOperatorEnable bool              `yaml:"prometheusOperatorEnable"`
+	PrometheusEnable         bool              `yaml:"prometheusEnable"`
+	PrometheusStorageSize    string            `yaml:"prometheusStorageSize"`
+	PrometheusStorageClass   string            `yaml:"prometheusStorageClass"`
+	PrometheusNodeSelector   map[string]string `yaml:"prometheusNodeSelector"`
+	DefaultSvcMonitorsEnable bool              `yaml:"defaultSvcMonitorsEnable"`
+	CnvrgIdleMetricsEnable   bool              `yaml:"cnvrgIdleMetricsEnable"`
+	CnvrgIdleMetricsLabels   map[string]string `yaml:"cnvrgIdleMetricsLabels"`
 }
 
-// Template struct for the values.tmpl file
+// Template struct for the values written to the values file
 type Template struct {
-	ClusterDomain        ClusterDomain
-	ClusterInteralDomain ClusterInteralDomain
-	Labels               Labels
-	Annotations          Annotations
-	Network              Networking
-	Logging              Logging
-	Registry             Registry
-	Tenancy              Tenancy
-	Sso                  Sso
-	Storage              Storage
-	ConfigReloader       ConfigReloader
-	Capsule              Capsule
-	Backup               Backup
-	Gpu                  Gpu
-	Monitoring           Monitoring
-	ControlPlane         ControlPlane
-	Dbs                  Dbs
+	ClusterDomain        ClusterDomain        `yaml:"clusterDomain"`
+	ClusterInteralDomain ClusterInteralDomain `yaml:"clusterInternalDomain"`
+	Labels               Labels               `yaml:"labels"`
+	Annotations          Annotations          `yaml:"annotations"`
+	Network              Networking           `yaml:"network"`
+	Logging              Logging              `yaml:"logging"`
+	Registry             Registry             `yaml:"registry"`
+	Tenancy              Tenancy              `yaml:"tenancy"`
+	Sso                  Sso                  `yaml:"sso"`
+	Storage              Storage              `yaml:"storage"`
+	ConfigReloader       ConfigReloader       `yaml:"configReloader"`
+	Capsule              Capsule              `yaml:"capsule"`
+	Backup               Backup               `yaml:"backup"`
+	Gpu                  Gpu                  `yaml:"gpu"`
+	Monitoring           Monitoring           `yaml:"monitoring"`
+	ControlPlane         ControlPlane         `yaml:"controlPlane"`
+	Dbs                  Dbs                  `yaml:"dbs"`
+}
+
+// Returns a Template with all of the defaults needed for templating
+func defaultTemplate() Template {
+	return Template{
+		ClusterInteralDomain: ClusterInteralDomain{Domain: "cluster.local"},
+		Network:              Networking{Istio: Istio{Enabled: true}, Ingress: Ingress{IstioGwEnabled: true}},
+		Logging:              Logging{FluentbitEnable: true, ElastalertEnable: true, KibanaEnable: true},
+		Storage:              Storage{Hostpath: Hostpath{Path: "/cnvrg-hostpath-storage"}},
+		Gpu:                  Gpu{NvidiaEnable: true, HabanaEnable: true},
+		Backup:               Backup{Enabled: true},
+		Capsule:              Capsule{Enabled: true},
+		ConfigReloader:       ConfigReloader{Enabled: true},
+		Monitoring: Monitoring{DcgmExportEnable: true, HabanaExportEnable: true, NodeExportEnable: true, KubeStateMetricEnable: true,
+			GrafanaEnable: true, PrometheusOperatorEnable: true, PrometheusEnable: true, DefaultSvcMonitorsEnable: true, CnvrgIdleMetricsEnable: true},
+		ControlPlane: ControlPlane{HyperEnable: true, CnvrgScheduleEnable: true, SearchkiqEnable: true, SidekiqEnable: true, SystemkiqEnable: true,
+			WebappEnable: true, MpiEnable: true, SearchkiqHpaEnable: true, SidekiqHpaEnable: true, SystemkiqHpaEnable: true, WebappHpaEnable: true},
+		Dbs: Dbs{EsEnable: true, MinioEnable: true, PgEnable: true, RedisEnable: true},
+	}
+}
+
+// Returns a Template built from the global structs set by the menus
+func currentTemplate() Template {
+	return Template{clusterdomain, internalDomain, labels, annotations, network, logging, registry, tenancy,
+		sso, storage, configreloader, capsule, backup, gpu, monitoring, controlplane, dbs}
+}
+
+// Sets every global struct used by the menus from the Template
+func applyTemplate(t Template) {
+	clusterdomain = t.ClusterDomain
+	internalDomain = t.ClusterInteralDomain
+	labels = t.Labels
+	annotations = t.Annotations
+	network = t.Network
+	logging = t.Logging
+	registry = t.Registry
+	tenancy = t.Tenancy
+	sso = t.Sso
+	storage = t.Storage
+	configreloader = t.ConfigReloader
+	capsule = t.Capsule
+	backup = t.Backup
+	gpu = t.Gpu
+	monitoring = t.Monitoring
+	controlplane = t.ControlPlane
+	dbs = t.Dbs
 }
 
 /* This struc includes clusterDomain, clusterInternalDomain,
 spec and imageHub used with gatherClusterDomain function.
 */
 type ClusterDomain struct {
-	ClusterDomain string
-	Spec          string
-	ImageHub      string
+	ClusterDomain string `yaml:"clusterDomain"`
+	Spec          string `yaml:"spec"`
+	ImageHub      string `yaml:"imageHub"`
 }
 
 type ClusterInteralDomain struct {
-	Domain string
+	Domain string `yaml:"domain"`
 }
 
 /* function used to leverage the ClusterDomain struct
@@ -312,8 +360,7 @@ func gatherClusterDomain(cluster *ClusterDomain) {
 	InfoLogger.Println("In the gatherClusterDomain function")
 
 	// Ask what the wildcard domain is
-	fmt.Print((colorBlue), "What is your wildcard domain? ")
-	clusterDomain := formatInput()
+	clusterDomain := promptLower("What is your wildcard domain? ")
 	cluster.ClusterDomain = clusterDomain
 
 }
@@ -324,12 +371,9 @@ func gatherInternalDomain(domain *ClusterInteralDomain) {
 	for {
 		fmt.Println((colorBlue), "Press '1' to modify Internal Cluster Domain [default: cluster.local]")
 		fmt.Println((colorBlue), "Press '2' to Save and Exit")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		input := formatInput()
-		intVar, _ := strconv.Atoi(input)
+		intVar := promptMenu("Internal Domain Menu", 2)
 		if intVar == 1 {
-			fmt.Print((colorWhite), "Please enter the internal cluster domain: ")
-			clusterInput := formatInput()
+			clusterInput := promptLower("Please enter the internal cluster domain: ")
 			domain.Domain = clusterInput
 			InfoLogger.Printf("Setting the internal cluster domain to %v\n", domain.Domain)
 		}
@@ -340,10 +384,7 @@ func gatherInternalDomain(domain *ClusterInteralDomain) {
 	}
 }
 
-type Labels struct {
-	Key       []string
-	Stringify string
-}
+type Labels map[string]string
 
 /* function used to leverage the Labels struct
 and to prompt user for all Labels settings this
@@ -351,28 +392,16 @@ will return a struct
 */
 func gatherLabels(labels *Labels) {
 	InfoLogger.Println("In the gatherLabels function")
-	scanner := bufio.NewScanner(os.Stdin)
-
-	for {
-		fmt.Print((colorWhite), "Add Label, format [key: value]; 'return' when done: ")
-		scanner.Scan()
-		text := scanner.Text()
 
-		if len(text) != 0 {
-			labels.Key = append(labels.Key, text)
-		} else {
-			break
-		}
+	if *labels == nil {
+		*labels = Labels{}
 	}
-	for _, v := range labels.Key {
-		labels.Stringify += fmt.Sprintf("%s, ", v)
+	for key, value := range promptMap("Add Label, format [key: value]; 'return' when done: ") {
+		(*labels)[key] = value
 	}
 }
 
-type Annotations struct {
-	Key       []string
-	Stringify string
-}
+type Annotations map[string]string
 
 /* function used to leverage the Annotations struct
 and to prompt user for all Annotations settings this
@@ -380,73 +409,52 @@ will return a struct
 */
 func gatherAnnotations(annotations *Annotations) {
 	InfoLogger.Println("In the gatherAnnotations function")
-	scanner := bufio.NewScanner(os.Stdin)
-
-	for {
-		fmt.Print((colorWhite), "Add Annotation, format [key: value]; 'return' when done: ")
-		scanner.Scan()
-
-		text := scanner.Text()
 
-		if len(text) != 0 {
-			annotations.Key = append(annotations.Key, text)
-		} else {
-			break
-		}
+	if *annotations == nil {
+		*annotations = Annotations{}
 	}
-
-	for _, v := range annotations.Key {
-		annotations.Stringify += fmt.Sprintf("%s, ", v)
+	for key, value := range promptMap("Add Annotation, format [key: value]; 'return' when done: ") {
+		(*annotations)[key] = value
 	}
 }
 
 // Parent level of the Networking struct
 type Networking struct {
-	Https   HttpsValues
-	Proxy   Proxy
-	Ingress Ingress
-	Istio   Istio
+	Https   HttpsValues `yaml:"https"`
+	Proxy   Proxy       `yaml:"proxy"`
+	Ingress Ingress     `yaml:"ingress"`
+	Istio   Istio       `yaml:"istio"`
 }
 
 // Used in the Networking struct
 type HttpsValues struct {
-	Enabled    bool
-	CertSecret string
+	Enabled    bool   `yaml:"enabled"`
+	CertSecret string `yaml:"certSecret"`
 }
 
 // Used in the Networking struct
 type Proxy struct {
-	Enabled    bool
-	HttpProxy  string
-	HttpsProxy string
-	NoProxy    string
+	Enabled    bool     `yaml:"enabled"`
+	HttpProxy  []string `yaml:"httpProxy"`
+	HttpsProxy []string `yaml:"httpsProxy"`
+	NoProxy    []string `yaml:"noProxy"`
 }
 
 // Used in the Networking struct
 type Ingress struct {
-	Type           string
-	IstioGwEnabled bool
-	IstioGwName    string
-	External       bool
+	Type           string `yaml:"type"`
+	IstioGwEnabled bool   `yaml:"istioGwEnabled"`
+	IstioGwName    string `yaml:"istioGwName"`
+	External       bool   `yaml:"external"`
 }
 
 // Used in the Networking struct
 type Istio struct {
-	Enabled               bool
-	ExternalIp            string
-	IngressSvcAnnotations string
-	IngressSvcExtraPorts  string
-	LbSourceRanges        string
-}
-
-// This function will format strings to lowercase and remove
-// any whitespace around the string the value is returned.
-func formatInput() string {
-	consoleReader := bufio.NewReader(os.Stdin)
-	input, _ := consoleReader.ReadString('\n')
-	input = strings.ToLower(input)
-	input = strings.TrimSpace(input)
-	return input
+	Enabled               bool              `yaml:"enabled"`
+	ExternalIp            []string          `yaml:"externalIp"`
+	IngressSvcAnnotations map[string]string `yaml:"ingressSvcAnnotations"`
+	IngressSvcExtraPorts  []string          `yaml:"ingressSvcExtraPorts"`
+	LbSourceRanges        []string          `yaml:"lbSourceRanges"`
 }
 
 // Outputs to std.out the helm commands which need to be ran for installation
@@ -460,58 +468,17 @@ func outputHelm() {
 	fmt.Println()
 	fmt.Println((colorGreen), "---------Helm Install Command---------")
 	fmt.Println((colorGreen), "Run the following Helm command to install cnvrg.io")
-	fmt.Println((colorWhite), "helm install cnvrg cnvrgv3/cnvrg --create-namespace -n cnvrg --timeout 1500s --wait --values ./values.yaml")
-}
-
-// The function prompts for a key value value
-// Takes the key value and returns a string
-func createArray() string {
-	InfoLogger.Println("In the createArray function")
-	scanner := bufio.NewScanner(os.Stdin)
-	var key []string
-	var stringify string
-
-	for {
-		fmt.Print((colorWhite), "Format [key: value]; 'return' when done: ")
-		scanner.Scan()
-		text := scanner.Text()
-
-		if len(text) != 0 {
-			key = append(key, text)
-		} else {
-			break
-		}
-	}
-	for _, v := range key {
-		stringify += fmt.Sprintf("%s, ", v)
-
-	}
-	return stringify
-
-}
-
-// This function will return a slice as a string. You can enter
-// any number of values one line at a time.
-func createSlice() string {
-	InfoLogger.Println("In the createSlice function")
-	fmt.Println((colorWhite), "Enter 1 value per line. Press 'return' when done: ")
-	consoleScanner := bufio.NewScanner(os.Stdin)
-	var slice []string
-	var finalSlice string
-
-	for {
-		consoleScanner.Scan()
-		text := consoleScanner.Text()
-		if len(text) != 0 {
-			slice = append(slice, text)
-		} else {
-			break
-		}
-	}
-	for _, v := range slice {
-		finalSlice += fmt.Sprintf("%s, ", v)
+	if secretsMode == secretsSeparate {
+		fmt.Printf("%v helm install cnvrg cnvrgv3/cnvrg --create-namespace -n cnvrg --timeout 1500s --wait --values ./%v --values ./%v\n", colorWhite, valuesFile, secretsFile)
+		fmt.Println()
+		fmt.Println((colorGreen), "Or let the cli run Helm for you")
+		fmt.Printf("%v cnvrg-deploy-cli install --values %v --secrets-file %v\n", colorWhite, valuesFile, secretsFile)
+		return
 	}
-	return finalSlice
+	fmt.Println((colorWhite), "helm install cnvrg cnvrgv3/cnvrg --create-namespace -n cnvrg --timeout 1500s --wait --values ./values.yaml")
+	fmt.Println()
+	fmt.Println((colorGreen), "Or let the cli run Helm for you")
+	fmt.Printf("%v cnvrg-deploy-cli install --values %v\n", colorWhite, valuesFile)
 }
 
 /* function used to leverate the Networking struct
@@ -530,9 +497,7 @@ func gatherNetworking(network *Networking) {
 		fmt.Println((colorBlue), "Press '3' for HTTPS Settings")
 		fmt.Println((colorBlue), "Press '4' for Istio Settings")
 		fmt.Println((colorBlue), "Press '5' to Save and Exit Network Menu")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
+		intVar := promptMenu("Networking Menu", 5)
 		switch intVar {
 		case 1:
 			InfoLogger.Println("In case statement 1 - Proxy")
@@ -545,27 +510,22 @@ func gatherNetworking(network *Networking) {
 				fmt.Println((colorBlue), "Press '3' to input HTTPS proxies to use")
 				fmt.Println((colorBlue), "Press '4' to input extra No Proxy values to use")
 				fmt.Println((colorBlue), "Press '5' to Save and Exit Proxy settings")
-				fmt.Print((colorWhite), "Please make your selection: ")
-				caseInput := formatInput()
-				intVar, _ := strconv.Atoi(caseInput)
+				intVar := promptMenu("Proxy Menu", 5)
 				switch intVar {
 				case 1:
 					network.Proxy.Enabled = true
 					fmt.Println((colorYellow), "Proxy enabled")
 					InfoLogger.Printf("Network Proxy set to %v\n", network.Proxy.Enabled)
 				case 2:
-					fmt.Println((colorBlue), "Please enter a list of HTTP proxies")
-					slice := createSlice()
+					slice := promptList("Please enter a list of HTTP proxies", checkURL)
 					network.Proxy.HttpProxy = slice
 					network.Proxy.Enabled = true
 				case 3:
-					fmt.Println((colorBlue), "Please enter a list of HTTPS proxies")
-					slice := createSlice()
+					slice := promptList("Please enter a list of HTTPS proxies", checkURL)
 					network.Proxy.HttpsProxy = slice
 					network.Proxy.Enabled = true
 				case 4:
-					fmt.Println((colorBlue), "Please enter a list of No proxies")
-					slice := createSlice()
+					slice := promptList("Please enter a list of No proxies", nil)
 					network.Proxy.NoProxy = slice
 					network.Proxy.Enabled = true
 				}
@@ -582,14 +542,16 @@ func gatherNetworking(network *Networking) {
 				fmt.Println((colorGreen), "Update Ingress values")
 				fmt.Println((colorBlue), "Press '1' to modify Ingress Type")
 				fmt.Println((colorBlue), "Press '2' to Save and Exit Ingress Menu")
-				fmt.Print((colorWhite), "Please make your selection: ")
-				caseInput := formatInput()
-				intVar, _ := strconv.Atoi(caseInput)
+				intVar := promptMenu("Ingress Menu", 2)
 				switch intVar {
 				case 1:
-					fmt.Print((colorWhite), "What is the ingress type [istio|ingress|openshift|nodeport]?: ")
-					ingressType := formatInput()
-					if ingressType == "istio" {
+					ingressType := promptEnum("What is the ingress type [istio|ingress|openshift|nodeport]?: ", "istio", "ingress", "openshift", "nodeport")
+					switch ingressType {
+					case "istio":
+						network.Ingress.Type = "istio"
+						network.Ingress.IstioGwEnabled = true
+						network.Istio.Enabled = true
+						fmt.Printf("Set Ingress to '%v' and Enabled Istio\n", ingressType)
 						for {
 							fmt.Println((colorGreen), "----Istio Menu----")
 							fmt.Println((colorGreen), "Update Istio values")
@@ -598,25 +560,20 @@ func gatherNetworking(network *Networking) {
 							fmt.Println((colorBlue), "Press '3' to modify Service Extra Ports")
 							fmt.Println((colorBlue), "Press '4' to modify Load Balance Source Ranges")
 							fmt.Println((colorBlue), "Press '5' to Save and Exit")
-							fmt.Print((colorWhite), "Please make your selection: ")
-							caseInput := formatInput()
-							intVar, _ := strconv.Atoi(caseInput)
+							intVar := promptMenu("Istio Menu", 5)
 							switch intVar {
 							case 1:
-								fmt.Print((colorWhite), "Input External IPs")
-								input := createSlice()
+								input := promptList("Input External IPs", checkIP)
 								network.Istio.ExternalIp = input
 							case 2:
-								fmt.Print((colorWhite), "Input Service Annotations")
-								input := createSlice()
+								fmt.Println((colorWhite), "Input Service Annotations")
+								input := promptMap("Format [key: value]; 'return' when done: ")
 								network.Istio.IngressSvcAnnotations = input
 							case 3:
-								fmt.Print((colorWhite), "Input Service Extra Ports")
-								input := createSlice()
+								input := promptList("Input Service Extra Ports", checkPort)
 								network.Istio.IngressSvcExtraPorts = input
 							case 4:
-								fmt.Print((colorWhite), "Input Load Balance Source Ranges")
-								input := createSlice()
+								input := promptList("Input Load Balance Source Ranges", checkCIDR)
 								network.Istio.LbSourceRanges = input
 							}
 							if intVar == 5 {
@@ -624,15 +581,14 @@ func gatherNetworking(network *Networking) {
 								break
 							}
 						}
-					}
-					if ingressType == "ingress" {
-						network.Ingress.Type = "ingress"
+					case "ingress", "nodeport":
+						network.Ingress.Type = ingressType
 						network.Istio.Enabled = false
 						fmt.Printf("Set Ingress to '%v' and Disabled Istio\n", ingressType)
-
-					}
-					if ingressType == "nodeport" {
-						network.Ingress.Type = "nodeport"
+					case "openshift":
+						// Routes are used instead of the Istio gateway, like the openshift profile
+						network.Ingress.Type = "openshift"
+						network.Ingress.IstioGwEnabled = false
 						network.Istio.Enabled = false
 						fmt.Printf("Set Ingress to '%v' and Disabled Istio\n", ingressType)
 					}
@@ -644,38 +600,16 @@ func gatherNetworking(network *Networking) {
 			}
 		case 3:
 			InfoLogger.Println("In case statement 3 - HTTPS")
-			for {
-				// Ask if they want to enable https and skip if "no"
-				fmt.Print("Do you want to enable HTTPS? (yes/no): ")
-				caseThreeInput := formatInput()
-
-				if caseThreeInput == "yes" {
-					network.Https.Enabled = true
-					InfoLogger.Printf("The HTTPS network setting is %v\n", network.Https.Enabled)
-					break
-				}
-				if caseThreeInput == "no" {
-					network.Https.Enabled = false
-					InfoLogger.Printf("The HTTPS network setting is %v\n", network.Https.Enabled)
-					break
-				}
-			}
-			for {
-				fmt.Print((colorWhite), "Do you want to add a Certificate? (yes/no)")
-				certinput := formatInput()
-				if certinput == "yes" {
-					fmt.Print("What do you want to name the Certificate secret? ")
-					var certName string
-					fmt.Scan(&certName)
-					network.Https.CertSecret = certName
-					network.Https.Enabled = true
-					InfoLogger.Printf("The secret name is %s \n", certName)
-					break
-				}
-				if certinput == "no" {
-					InfoLogger.Println("Breaking for loop, not setting Certificate name")
-					break
-				}
+			// Ask if they want to enable https
+			network.Https.Enabled = promptBool("Do you want to enable HTTPS? (yes/no): ")
+			InfoLogger.Printf("The HTTPS network setting is %v\n", network.Https.Enabled)
+			if promptBool("Do you want to add a Certificate? (yes/no): ") {
+				certName := promptString("What do you want to name the Certificate secret? ")
+				network.Https.CertSecret = certName
+				network.Https.Enabled = true
+				InfoLogger.Printf("The secret name is %s \n", certName)
+			} else {
+				InfoLogger.Println("Not setting Certificate name")
 			}
 		case 4:
 			for {
@@ -688,32 +622,27 @@ func gatherNetworking(network *Networking) {
 				fmt.Println((colorBlue), "Press '4' list extra LB sources ranges")
 				fmt.Println((colorBlue), "Press '5' map of strings for Istio SVC annotations")
 				fmt.Println((colorBlue), "Press '6' to Save and Exit Istio menu")
-				fmt.Print((colorWhite), "Please make your selection: ")
-				caseInput := formatInput()
-				intVar, _ := strconv.Atoi(caseInput)
+				intVar := promptMenu("Istio Menu", 6)
 				switch intVar {
 				case 1:
 					network.Istio.Enabled = false
 					fmt.Println((colorYellow), "Istio is disabled")
 					InfoLogger.Printf("Istio set to %v", network.Istio.Enabled)
 				case 2:
-					fmt.Println((colorWhite), "Please enter a list of IPs to use for Istio ingress service: ")
-					slice := createSlice()
+					slice := promptList("Please enter a list of IPs to use for Istio ingress service", checkIP)
 					network.Istio.ExternalIp = slice
 					network.Istio.Enabled = true
 				case 3:
-					fmt.Println((colorWhite), "Please enter a list extra ports for Istio ingress service: ")
-					slice := createSlice()
+					slice := promptList("Please enter a list extra ports for Istio ingress service", checkPort)
 					network.Istio.IngressSvcExtraPorts = slice
 					network.Istio.Enabled = true
 				case 4:
-					fmt.Println((colorWhite), "Please enter a list of extra LB sources ranges: ")
-					slice := createSlice()
+					slice := promptList("Please enter a list of extra LB sources ranges", checkCIDR)
 					network.Istio.LbSourceRanges = slice
 					network.Istio.Enabled = true
 				case 5:
 					fmt.Println((colorWhite), "Please enter Istio SVC annotations: ")
-					slice := createArray()
+					slice := promptMap("Format [key: value]; 'return' when done: ")
 					network.Istio.IngressSvcAnnotations = slice
 					network.Istio.Enabled = true
 				}
@@ -751,9 +680,7 @@ func gatherMonitoring(monitoring *Monitoring) {
 		fmt.Println((colorBlue), "Press '8' To disable Default Svc Monitoring")
 		fmt.Println((colorBlue), "Press '9' To disable cnvrg Idle Metrics")
 		fmt.Println((colorBlue), "Press '10' To Save and Exit")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
+		intVar := promptMenu("Monitoring Menu", 10)
 		switch intVar {
 		case 1:
 			monitoring.DcgmExportEnable = false
@@ -804,11 +731,13 @@ func gatherControlPlane(controlplane *ControlPlane) {
 		fmt.Println((colorBlue), "Press '5' To disable Sidekiq")
 		fmt.Println((colorBlue), "Press '6' To disable Systemkiq")
 		fmt.Println((colorBlue), "Press '7' To disable Webapp")
-		fmt.Println((colorBlue), "Press '8' To disable MPI")
-		fmt.Println((colorBlue), "Press '9' To Save and Exit")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
+		fmt.Println((colorBlue), "Press '8' To modify MPI settings")
+		fmt.Println((colorBlue), "Press '9' To modify SMTP settings")
+		fmt.Println((colorBlue), "Press '10' To modify Object Storage settings")
+		fmt.Println((colorBlue), "Press '11' To modify Replicas and Autoscaling")
+		fmt.Println((colorBlue), "Press '12' To modify the Image and Base Config")
+		fmt.Println((colorBlue), "Press '13' To Save and Exit")
+		intVar := promptMenu("ControlPlane Menu", 13)
 		switch intVar {
 		case 1:
 			controlplane.HyperEnable = false
@@ -832,12 +761,346 @@ func gatherControlPlane(controlplane *ControlPlane) {
 			controlplane.WebappEnable = false
 			fmt.Println((colorYellow), "Webapp Disabled")
 		case 8:
-			controlplane.MpiEnable = false
-			fmt.Println((colorYellow), "MPI Disabled")
+			gatherMpi(controlplane)
+		case 9:
+			gatherSmtp(controlplane)
+		case 10:
+			gatherObjectStorage(controlplane)
+		case 11:
+			gatherScaling(controlplane)
+		case 12:
+			gatherBaseConfig(controlplane)
+		}
+		if intVar == 13 {
+			fmt.Println((colorYellow), "Saving and Exiting ControlPlane Settings")
+			break
+		}
+	}
+}
+
+/* Function used to gather the image and the base config of the
+Control Plane, used to pin the cnvrg.io version of an environment
+*/
+func gatherBaseConfig(controlplane *ControlPlane) {
+	InfoLogger.Println("In the gatherBaseConfig function")
+
+	for {
+		fmt.Println()
+		fmt.Println((colorGreen), "----Image and Base Config Menu----")
+		fmt.Println((colorGreen), "Update the Control Plane image and base config values")
+		fmt.Println((colorBlue), "Press '1' To modify the Control Plane Image")
+		fmt.Println((colorBlue), "Press '2' To modify the Agent Custom Tag")
+		if controlplane.BaseConfigIntercom {
+			fmt.Println((colorBlue), "Press '3' To disable Intercom")
+		} else {
+			fmt.Println((colorBlue), "Press '3' To enable Intercom")
+		}
+		if controlplane.BaseConfigCnvrgPrivileged {
+			fmt.Println((colorBlue), "Press '4' To disable cnvrg Privileged Jobs")
+		} else {
+			fmt.Println((colorBlue), "Press '4' To enable cnvrg Privileged Jobs")
+		}
+		fmt.Println((colorBlue), "Press '5' To modify the Feature Flags")
+		fmt.Println((colorBlue), "Press '6' To Save and Exit Image and Base Config menu")
+		intVar := promptMenu("Image and Base Config Menu", 6)
+		switch intVar {
+		case 1:
+			controlplane.Image = promptString("Input the Control Plane Image [e.g. cnvrg/app:v4.7.33]: ")
+		case 2:
+			controlplane.BaseConfigAgentTag = promptString("Input the Agent Custom Tag: ")
+		case 3:
+			controlplane.BaseConfigIntercom = !controlplane.BaseConfigIntercom
+		case 4:
+			controlplane.BaseConfigCnvrgPrivileged = !controlplane.BaseConfigCnvrgPrivileged
+		case 5:
+			fmt.Println((colorWhite), "Input the Feature Flags, a flag with an empty value is removed")
+			flags := promptMap("Format [flag: value]; 'return' when done: ")
+			if controlplane.BaseConfigFeatureFlags == nil {
+				controlplane.BaseConfigFeatureFlags = map[string]string{}
+			}
+			for key, value := range flags {
+				if value == "" {
+					delete(controlplane.BaseConfigFeatureFlags, key)
+					continue
+				}
+				controlplane.BaseConfigFeatureFlags[key] = value
+			}
+		}
+		if intVar == 6 {
+			fmt.Println((colorYellow), "Saving and Exiting Image and Base Config settings")
+			break
+		}
+	}
+}
+
+/* Function used to gather the MPI operator values of the Control Plane,
+used to pull the MPI images from a private registry
+*/
+func gatherMpi(controlplane *ControlPlane) {
+	InfoLogger.Println("In the gatherMpi function")
+
+	for {
+		fmt.Println()
+		fmt.Println((colorGreen), "----MPI Menu----")
+		fmt.Println((colorGreen), "Update the MPI operator values")
+		if controlplane.MpiEnable {
+			fmt.Println((colorBlue), "Press '1' To disable MPI")
+		} else {
+			fmt.Println((colorBlue), "Press '1' To enable MPI")
+		}
+		fmt.Println((colorBlue), "Press '2' To modify the MPI Image")
+		fmt.Println((colorBlue), "Press '3' To modify the Kubectl Delivery Image")
+		fmt.Println((colorBlue), "Press '4' To modify the Extra Args")
+		fmt.Println((colorBlue), "Press '5' To modify the Registry URL")
+		fmt.Println((colorBlue), "Press '6' To modify the Registry User Name")
+		fmt.Println((colorBlue), "Press '7' To modify the Registry Password")
+		fmt.Println((colorBlue), "Press '8' To Save and Exit MPI menu")
+		intVar := promptMenu("MPI Menu", 8)
+		switch intVar {
+		case 1:
+			controlplane.MpiEnable = !controlplane.MpiEnable
+			if controlplane.MpiEnable {
+				fmt.Println((colorYellow), "MPI Enabled")
+			} else {
+				fmt.Println((colorYellow), "MPI Disabled")
+			}
+		case 2:
+			controlplane.MpiImage = promptString("Input the MPI Image: ")
+			controlplane.MpiEnable = true
+		case 3:
+			controlplane.MpiKubectlImage = promptString("Input the Kubectl Delivery Image: ")
+			controlplane.MpiEnable = true
+		case 4:
+			fmt.Println((colorWhite), "Input the Extra Args, an arg with an empty value is removed")
+			args := promptMap("Format [--arg: value]; 'return' when done: ")
+			if controlplane.MpiExtraArgs == nil {
+				controlplane.MpiExtraArgs = map[string]string{}
+			}
+			for key, value := range args {
+				if value == "" {
+					delete(controlplane.MpiExtraArgs, key)
+					continue
+				}
+				controlplane.MpiExtraArgs[key] = value
+			}
+			controlplane.MpiEnable = true
+		case 5:
+			controlplane.MpiRegistryUrl = promptString("Input the Registry URL: ")
+			controlplane.MpiEnable = true
+		case 6:
+			controlplane.MpiRegistryUser = promptString("Input the Registry User Name: ")
+			controlplane.MpiEnable = true
+		case 7:
+			controlplane.MpiRegistryPassword = readSecret("Input the Registry Password: ")
+			controlplane.MpiEnable = true
+		}
+		if intVar == 8 {
+			fmt.Println((colorYellow), "Saving and Exiting MPI settings")
+			break
+		}
+	}
+}
 
+/* Function used to gather the replicas and the HPA values of the
+Control Plane services, the max replicas of the HPA are checked
+against the replicas of the service
+*/
+func gatherScaling(controlplane *ControlPlane) {
+	InfoLogger.Println("In the gatherScaling function")
+
+	for {
+		fmt.Println()
+		fmt.Println((colorGreen), "----Replicas and Autoscaling Menu----")
+		fmt.Println((colorGreen), "Select the service to scale")
+		for i, s := range scalingServices {
+			fmt.Printf("%v Press '%d' To modify %v\n", colorBlue, i+1, s.label)
+		}
+		exit := len(scalingServices) + 1
+		fmt.Printf("%v Press '%d' To Save and Exit Replicas and Autoscaling menu\n", colorBlue, exit)
+		intVar := promptMenu("Replicas and Autoscaling Menu", exit)
+		if intVar == exit {
+			fmt.Println((colorYellow), "Saving and Exiting Replicas and Autoscaling settings")
+			break
+		}
+		if intVar < 1 || intVar > len(scalingServices) {
+			continue
+		}
+		service := scalingServices[intVar-1]
+
+		for {
+			hpa := "enabled"
+			if !*service.hpaEnable(controlplane) {
+				hpa = "disabled"
+			}
+			fmt.Println()
+			fmt.Printf("%v ----%v Scaling Menu----\n", colorGreen, service.label)
+			if service.replicas != nil {
+				fmt.Printf("%v Replicas: %d\n", colorGreen, service.currentReplicas(controlplane))
+			}
+			fmt.Printf("%v HPA: %v, HPA Max Replicas: %d\n", colorGreen, hpa, *service.hpaMax(controlplane))
+			if service.replicas != nil {
+				fmt.Println((colorBlue), "Press '1' To modify Replicas")
+			}
+			fmt.Println((colorBlue), "Press '2' To enable or disable the HPA")
+			fmt.Println((colorBlue), "Press '3' To modify the HPA Max Replicas")
+			if service.name == "sidekiq" {
+				fmt.Println((colorBlue), "Press '4' To enable or disable Sidekiq Split")
+			}
+			fmt.Printf("%v Press '5' To Save and Exit %v menu\n", colorBlue, service.label)
+			intVar := promptMenu(service.label+" Scaling Menu", 5)
+			switch intVar {
+			case 1:
+				if service.replicas == nil {
+					continue
+				}
+				replicas := promptInt("Input the Replicas: ", 1, 1000)
+				if max := *service.hpaMax(controlplane); max != 0 && max < replicas {
+					fmt.Printf("%v The replicas must not be more than the %d HPA max replicas\n", colorYellow, max)
+					continue
+				}
+				*service.replicas(controlplane) = replicas
+			case 2:
+				*service.hpaEnable(controlplane) = !*service.hpaEnable(controlplane)
+				if !*service.hpaEnable(controlplane) {
+					*service.hpaMax(controlplane) = 0
+				}
+			case 3:
+				if !*service.hpaEnable(controlplane) {
+					fmt.Println((colorYellow), "Enable the HPA before setting the max replicas")
+					continue
+				}
+				max := promptInt("Input the HPA Max Replicas: ", 1, 1000)
+				if msg := checkHpaMaxReplicas(service, controlplane, max); msg != "" {
+					fmt.Println((colorYellow), "The max replicas", msg)
+					continue
+				}
+				*service.hpaMax(controlplane) = max
+			case 4:
+				if service.name == "sidekiq" {
+					controlplane.SidekiqSplit = !controlplane.SidekiqSplit
+				}
+			}
+			if intVar == 5 {
+				break
+			}
+		}
+	}
+}
+
+/* Function used to gather the object storage values of the Control Plane.
+The type is selected first and then only the values used by that type
+are asked for, values of the previous type are cleared
+*/
+func gatherObjectStorage(controlplane *ControlPlane) {
+	InfoLogger.Println("In the gatherObjectStorage function")
+
+	for {
+		fmt.Println()
+		fmt.Println((colorGreen), "----Object Storage Menu----")
+		fmt.Println((colorGreen), "Select the object storage type to modify its values")
+		for i, p := range objectStorageProviders {
+			fmt.Printf("%v Press '%d' To use %v object storage\n", colorBlue, i+1, p.name)
+		}
+		exit := len(objectStorageProviders) + 1
+		fmt.Printf("%v Press '%d' To Save and Exit Object Storage menu\n", colorBlue, exit)
+		intVar := promptMenu("Object Storage Menu", exit)
+		if intVar == exit {
+			fmt.Println((colorYellow), "Saving and Exiting Object Storage settings")
+			break
+		}
+		if intVar < 1 || intVar > len(objectStorageProviders) {
+			continue
+		}
+
+		provider := objectStorageProviders[intVar-1]
+		controlplane.ObjectStorageType = provider.name
+		for _, f := range objectStorageFields {
+			if !provider.uses(f.name) {
+				*f.field(controlplane) = ""
+			}
+		}
+		for _, name := range provider.fields {
+			f, ok := findObjectStorageField(name)
+			if !ok {
+				ErrorLogger.Printf("Unknown object storage value %v\n", name)
+				continue
+			}
+			prompt := fmt.Sprintf("Input the %v ['return' to keep %q]: ", f.label, *f.field(controlplane))
+			var value string
+			if f.secret {
+				if *f.field(controlplane) != "" {
+					prompt = fmt.Sprintf("Input the %v ['return' to keep the current value]: ", f.label)
+				}
+				value = readSecret(prompt)
+			} else {
+				value = promptFor(prompt, optional(f.check))
+			}
+			if value != "" {
+				*f.field(controlplane) = value
+			}
+		}
+		fmt.Printf("%v Object storage set to %v\n", colorGreen, provider.name)
+
+		if provider.uses("endpoint") {
+			if promptConfirm("Test the connection to the bucket? [y/N]: ") {
+				if err := probeObjectStorage(*controlplane); err != nil {
+					ErrorLogger.Println(err)
+					fmt.Println((colorYellow), err)
+				} else {
+					fmt.Println((colorGreen), "Connected to the bucket")
+				}
+			}
+		}
+	}
+}
+
+/* Function used to gather the SMTP values of the Control Plane
+through menu driven options, every value is checked before it
+is saved
+*/
+func gatherSmtp(controlplane *ControlPlane) {
+	InfoLogger.Println("In the gatherSmtp function")
+
+	for {
+		fmt.Println()
+		fmt.Println((colorGreen), "----SMTP Menu----")
+		fmt.Println((colorGreen), "Update the SMTP values used to send email")
+		fmt.Println((colorBlue), "Press '1' To modify the SMTP Server")
+		fmt.Println((colorBlue), "Press '2' To modify the Port [default: 587]")
+		fmt.Println((colorBlue), "Press '3' To modify the User Name")
+		fmt.Println((colorBlue), "Press '4' To modify the Password")
+		fmt.Println((colorBlue), "Press '5' To modify the Domain")
+		fmt.Println((colorBlue), "Press '6' To modify the OpenSSL Verify Mode")
+		fmt.Println((colorBlue), "Press '7' To modify the Sender email")
+		fmt.Println((colorBlue), "Press '8' To test the connection to the SMTP Server")
+		fmt.Println((colorBlue), "Press '9' To Save and Exit SMTP menu")
+		intVar := promptMenu("SMTP Menu", 9)
+		switch intVar {
+		case 1:
+			controlplane.SmtpServer = promptHost("Input the SMTP Server: ")
+		case 2:
+			controlplane.SmtpPort = promptInt("Input the Port: ", 1, 65535)
+		case 3:
+			controlplane.SmtpUsername = promptString("Input the User Name: ")
+		case 4:
+			controlplane.SmtpPassword = readSecret("Input the Password: ")
+		case 5:
+			controlplane.SmtpDomain = promptLower("Input the Domain: ")
+		case 6:
+			label := fmt.Sprintf("Input the OpenSSL Verify Mode [%v]: ", strings.Join(smtpVerifyModes, "|"))
+			controlplane.SmtpOpenSslMode = promptEnum(label, smtpVerifyModes...)
+		case 7:
+			controlplane.SmtpSender = promptFor("Input the Sender email: ", checkEmail)
+		case 8:
+			if err := testSmtp(*controlplane); err != nil {
+				ErrorLogger.Println(err)
+				fmt.Println((colorYellow), err)
+				continue
+			}
+			fmt.Println((colorGreen), "Connected to the SMTP Server")
 		}
 		if intVar == 9 {
-			fmt.Println((colorYellow), "Saving and Exiting ControlPlane Settings")
+			fmt.Println((colorYellow), "Saving and Exiting SMTP settings")
 			break
 		}
 	}
@@ -859,9 +1122,7 @@ func gatherDbs(dbs *Dbs) {
 		fmt.Println((colorBlue), "Press '4' To modify Postgres")
 		fmt.Println((colorBlue), "Press '5' To modify Redis")
 		fmt.Println((colorBlue), "Press '6' To Save and Exit")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
+		intVar := promptMenu("Database Menu", 6)
 		switch intVar {
 		case 1:
 			dbs.CvatEnable = true
@@ -877,22 +1138,18 @@ func gatherDbs(dbs *Dbs) {
 				fmt.Println((colorBlue), "Press '4' To disable Patch Elastic Search Nodes")
 				fmt.Println((colorBlue), "Press '5' To modify Node Selector")
 				fmt.Println((colorBlue), "Press '6' To Save and Exit Elastic Search menu")
-				fmt.Print((colorWhite), "Please make your selection: ")
-				caseInput := formatInput()
-				intVar, _ := strconv.Atoi(caseInput)
+				intVar := promptMenu("Elastic Search Menu", 6)
 				switch intVar {
 				case 1:
 					dbs.EsEnable = false
 					fmt.Println((colorYellow), "Elastic Search disabled")
 				case 2:
-					fmt.Print((colorWhite), "Input Storage Size [default: 80Gi]: ")
-					caseInput := formatInput()
-					dbs.EsStorageSize = caseInput + "Gi"
+					if size := promptQuantity("Input Storage Size [default: 80Gi]: "); size != "" {
+						dbs.EsStorageSize = size
+					}
 					dbs.EsEnable = true
 				case 3:
-					fmt.Print((colorWhite), "Input Storage Class: ")
-					caseInput := formatInput()
-					dbs.EsStorageSize = caseInput
+					dbs.EsStorageClass = promptString("Input Storage Class: ")
 					dbs.EsEnable = true
 				case 4:
 					dbs.EsPatchNodes = false
@@ -900,7 +1157,7 @@ func gatherDbs(dbs *Dbs) {
 					dbs.EsEnable = true
 				case 5:
 					fmt.Print((colorWhite), "Input Node Selector values")
-					node := createSlice()
+					node := promptMap("Format [key: value]; 'return' when done: ")
 					dbs.EsNodeSelector = node
 					dbs.EsEnable = true
 				}
@@ -920,24 +1177,20 @@ func gatherDbs(dbs *Dbs) {
 				fmt.Println((colorBlue), "Press '3' To modify Storage Class")
 				fmt.Println((colorBlue), "Press '4' To modify Node Selector")
 				fmt.Println((colorBlue), "Press '5' To Save and Exit Elastic Search menu")
-				fmt.Print((colorWhite), "Please make your selection: ")
-				caseInput := formatInput()
-				intVar, _ := strconv.Atoi(caseInput)
+				intVar := promptMenu("Minio Menu", 5)
 				switch intVar {
 				case 1:
 					dbs.MinioEnable = false
 					fmt.Println((colorYellow), "Minio disabled")
 				case 2:
-					fmt.Print((colorWhite), "Input Storage Size [default: 100Gi]: ")
-					caseInput := formatInput()
-					dbs.MinioStorageSize = caseInput + "Gi"
+					if size := promptQuantity("Input Storage Size [default: 100Gi]: "); size != "" {
+						dbs.MinioStorageSize = size
+					}
 				case 3:
-					fmt.Print((colorWhite), "Input Storage Class: ")
-					caseInput := formatInput()
-					dbs.MinioStorageClass = caseInput
+					dbs.MinioStorageClass = promptString("Input Storage Class: ")
 				case 4:
 					fmt.Print((colorWhite), "Input Node Selector values")
-					node := createSlice()
+					node := promptMap("Format [key: value]; 'return' when done: ")
 					dbs.MinioNodeSelector = node
 				}
 				if intVar == 5 {
@@ -955,24 +1208,20 @@ func gatherDbs(dbs *Dbs) {
 				fmt.Println((colorBlue), "Press '3' To modify Storage Class")
 				fmt.Println((colorBlue), "Press '4' To modify Node Selector")
 				fmt.Println((colorBlue), "Press '5' To Save and Exit Postgres menu")
-				fmt.Print((colorWhite), "Please make your selection: ")
-				caseInput := formatInput()
-				intVar, _ := strconv.Atoi(caseInput)
+				intVar := promptMenu("Postgres Menu", 5)
 				switch intVar {
 				case 1:
 					dbs.PgEnable = false
 					fmt.Println((colorYellow), "Postgres disabled")
 				case 2:
-					fmt.Print((colorWhite), "Input Storage Size [default: 80Gi]: ")
-					caseInput := formatInput()
-					dbs.PgStorageSize = caseInput + "Gi"
+					if size := promptQuantity("Input Storage Size [default: 80Gi]: "); size != "" {
+						dbs.PgStorageSize = size
+					}
 				case 3:
-					fmt.Print((colorWhite), "Input Storage Class: ")
-					caseInput := formatInput()
-					dbs.PgStorageClass = caseInput
+					dbs.PgStorageClass = promptString("Input Storage Class: ")
 				case 4:
 					fmt.Print((colorWhite), "Input Node Selector values")
-					node := createSlice()
+					node := promptMap("Format [key: value]; 'return' when done: ")
 					dbs.PgNodeSelector = node
 				}
 				if intVar == 5 {
@@ -990,24 +1239,20 @@ func gatherDbs(dbs *Dbs) {
 				fmt.Println((colorBlue), "Press '3' To modify Storage Class")
 				fmt.Println((colorBlue), "Press '4' To modify Node Selector")
 				fmt.Println((colorBlue), "Press '5' To Save and Exit Redis menu")
-				fmt.Print((colorWhite), "Please make your selection: ")
-				caseInput := formatInput()
-				intVar, _ := strconv.Atoi(caseInput)
+				intVar := promptMenu("Redis Menu", 5)
 				switch intVar {
 				case 1:
 					dbs.RedisEnable = false
 					fmt.Println((colorYellow), "Postgres Redis")
 				case 2:
-					fmt.Print((colorWhite), "Input Storage Size [default: 10Gi]: ")
-					caseInput := formatInput()
-					dbs.RedisStorageSize = caseInput + "Gi"
+					if size := promptQuantity("Input Storage Size [default: 10Gi]: "); size != "" {
+						dbs.RedisStorageSize = size
+					}
 				case 3:
-					fmt.Print((colorWhite), "Input Storage Class: ")
-					caseInput := formatInput()
-					dbs.RedisStorageClass = caseInput
+					dbs.RedisStorageClass = promptString("Input Storage Class: ")
 				case 4:
 					fmt.Print((colorWhite), "Input Node Selector values")
-					node := createSlice()
+					node := promptMap("Format [key: value]; 'return' when done: ")
 					dbs.RedisNodeSelector = node
 				}
 				if intVar == 5 {
@@ -1038,9 +1283,7 @@ func gatherLogging(logging *Logging) {
 		fmt.Println((colorBlue), "Press '2' To disable Kibana")
 		fmt.Println((colorBlue), "Press '3' To configure Elastalert")
 		fmt.Println((colorBlue), "Press '4' To Save and Exit")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
+		intVar := promptMenu("Logging Menu", 4)
 		switch intVar {
 		case 1:
 			logging.FluentbitEnable = false
@@ -1053,35 +1296,29 @@ func gatherLogging(logging *Logging) {
 		case 3:
 			for {
 				fmt.Println()
-				fmt.Println((colorGreen), "----Elastic Search Menu----")
-				fmt.Println((colorGreen), "Update Elastic Search values")
+				fmt.Println((colorGreen), "----Elastalert Menu----")
+				fmt.Println((colorGreen), "Update Elastalert values")
 				fmt.Println((colorBlue), "Press '1' to disable Elastalert")
 				fmt.Println((colorBlue), "Press '2' to change the Storage Size")
 				fmt.Println((colorBlue), "Press '3' to change the Storage Class")
 				fmt.Println((colorBlue), "Press '4' to change the node Selector")
 				fmt.Println((colorBlue), "Press '5' to Save and Exit")
-				fmt.Print((colorWhite), "Please make your selection: ")
-				caseInput := formatInput()
-				intVar, _ := strconv.Atoi(caseInput)
+				intVar := promptMenu("Elastalert Menu", 5)
 				switch intVar {
 				case 1:
 					logging.ElastalertEnable = false
 					fmt.Println((colorYellow), "Elastalert is disabled")
 				case 2:
-					fmt.Print((colorWhite), "Input Storage Size [default: 30Gi]: ")
-					var storageSize string
-					fmt.Scan(&storageSize)
-					logging.ElastaStorageSize = storageSize + "Gi"
+					if size := promptQuantity("Input Storage Size [default: 30Gi]: "); size != "" {
+						logging.ElastaStorageSize = size
+					}
 					logging.ElastalertEnable = true
 				case 3:
-					fmt.Print((colorWhite), "Please enter the new Storage Class: ")
-					var storageClass string
-					fmt.Scan(&storageClass)
-					logging.ElastaStorageClass = storageClass
+					logging.ElastaStorageClass = promptString("Please enter the new Storage Class: ")
 					logging.ElastalertEnable = true
 				case 4:
 					fmt.Print((colorWhite), "Please enter the new Node Selector: ")
-					storageClass := createSlice()
+					storageClass := promptMap("Format [key: value]; 'return' when done: ")
 					logging.ElastaNodeSelector = storageClass
 					logging.ElastalertEnable = true
 				}
@@ -1111,9 +1348,7 @@ func gatherGpu(gpu *Gpu) {
 		fmt.Println((colorBlue), "Press '1' to disable Nvidia GPU")
 		fmt.Println((colorBlue), "Press '2' to disable Habana GPU")
 		fmt.Println((colorBlue), "Press '3' to Save and Exit")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
+		intVar := promptMenu("GPU Menu", 3)
 		switch intVar {
 		case 1:
 			gpu.NvidiaEnable = false
@@ -1143,25 +1378,19 @@ func gatherBackup(backup *Backup) {
 		fmt.Println((colorBlue), "Press '2' to modify Backup Rotation [default: 5]")
 		fmt.Println((colorBlue), "Press '3' to modify Backup Period [default: 24h]")
 		fmt.Println((colorBlue), "Press '4' to Save and Exit")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
+		intVar := promptMenu("Backup Menu", 4)
 		switch intVar {
 		case 1:
 			backup.Enabled = false
 			fmt.Println((colorYellow), "Backup is disabled")
 		case 2:
-			fmt.Print((colorBlue), "Input Backup Rotation [default: 5]: ")
-			caseInput := formatInput()
-			intVar, _ := strconv.Atoi(caseInput)
-			backup.Rotation = intVar
+			backup.Rotation = promptInt("Input Backup Rotation [default: 5]: ", 1, 1000)
 		case 3:
-			fmt.Print((colorBlue), "Input Backup Period [default: 24h]: ")
-			caseInput := formatInput()
-			if caseInput == "" {
+			period := promptDuration("Input Backup Period [default: 24h]: ")
+			if period == "" {
 				backup.Period = "24h"
 			} else {
-				backup.Period = caseInput
+				backup.Period = period
 			}
 		}
 		if intVar == 4 {
@@ -1185,17 +1414,13 @@ func gatherCapsule(capsule *Capsule) {
 		fmt.Println((colorBlue), "Press '1' to disable Capsule")
 		fmt.Println((colorBlue), "Press '2' to modify Capsule image")
 		fmt.Println((colorBlue), "Press '3' to Save and Exit")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
+		intVar := promptMenu("Capsule Menu", 3)
 		switch intVar {
 		case 1:
 			capsule.Enabled = false
 			fmt.Println((colorYellow), "Capsule is disabled")
 		case 2:
-			fmt.Print((colorBlue), "Please enter new image: ")
-			caseInput := formatInput()
-			capsule.Image = caseInput
+			capsule.Image = promptString("Please enter new image: ")
 		}
 		if intVar == 3 {
 			fmt.Println((colorYellow), "Saving and Exiting Capsule menu")
@@ -1221,8 +1446,6 @@ will return a struct
 func gatherRegistry(registry *Registry) {
 	InfoLogger.Println("In the gatherRegistry function")
 
-	var password string
-
 	for {
 		fmt.Println()
 		fmt.Println((colorGreen), "----Registry Menu----")
@@ -1231,13 +1454,10 @@ func gatherRegistry(registry *Registry) {
 		fmt.Println((colorBlue), "Press '2' to update Registry User Name")
 		fmt.Println((colorBlue), "Press '3' to update Registry Password")
 		fmt.Println((colorBlue), "Press '4' to Save and Exit")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
+		intVar := promptMenu("Registry Menu", 4)
 		switch intVar {
 		case 1:
-			fmt.Print((colorBlue), "Input the registry URL [default docker.io]: ")
-			url := formatInput()
+			url := promptString("Input the registry URL [default docker.io]: ")
 			if url == "" {
 				registry.Url = "docker.io"
 			} else {
@@ -1245,14 +1465,11 @@ func gatherRegistry(registry *Registry) {
 				registry.Url = url
 			}
 		case 2:
-			fmt.Print("Input the registry User Name: ")
-			user := formatInput()
+			user := promptString("Input the registry User Name: ")
 			registry.User = user
 			registry.Enabled = true
 		case 3:
-			fmt.Print("Input the registry Password: ")
-			fmt.Scanln(&password)
-			registry.Password = password
+			registry.Password = readSecret("Input the registry Password: ")
 			registry.Enabled = true
 		}
 		if intVar == 4 {
@@ -1277,22 +1494,18 @@ func gatherTenancy(tenancy *Tenancy) {
 		fmt.Println((colorBlue), "Press '2' to add Tenancy node selector key")
 		fmt.Println((colorBlue), "Press '3' to add Tenancy node selector value")
 		fmt.Println((colorBlue), "Press '4' to Save and Exit")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
+		intVar := promptMenu("Tenancy Menu", 4)
 		switch intVar {
 		case 1:
 			tenancy.Enabled = true
 			fmt.Println((colorYellow), "Tenancy Enabled")
 			InfoLogger.Printf("Tenancy enabled set to %v\n", tenancy.Enabled)
 		case 2:
-			fmt.Print((colorBlue), "Please enter the Tenancy node selector key: ")
-			key := formatInput()
+			key := promptString("Please enter the Tenancy node selector key: ")
 			tenancy.Key = key
 			tenancy.Enabled = true
 		case 3:
-			fmt.Print((colorBlue), "Please enter the Tenancy node selector value: ")
-			value := formatInput()
+			value := promptString("Please enter the Tenancy node selector value: ")
 			tenancy.Value = value
 			tenancy.Enabled = true
 		}
@@ -1317,9 +1530,7 @@ func gatherStorage(storage *Storage) {
 		fmt.Println((colorBlue), "Press '1' to modify HostPath settings")
 		fmt.Println((colorBlue), "Press '2' to modify NFS settings")
 		fmt.Println((colorBlue), "Press '3' to Save and Exit")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
+		intVar := promptMenu("Storage Menu", 3)
 		switch intVar {
 		case 1:
 			for {
@@ -1331,37 +1542,23 @@ func gatherStorage(storage *Storage) {
 				fmt.Println((colorBlue), "Press '3' to modify Reclaim Policy [default: Retain]")
 				fmt.Println((colorBlue), "Press '4' to modify Node Selector")
 				fmt.Println((colorBlue), "Press '5' to Save and Exit")
-				fmt.Print((colorWhite), "Please make your selection: ")
-				caseInput := formatInput()
-				intVar, _ := strconv.Atoi(caseInput)
+				intVar := promptMenu("HostPath Menu", 5)
 				switch intVar {
 				case 1:
 					storage.Hostpath.Enabled = true
 					storage.Hostpath.DefaultSc = true
 					fmt.Println((colorYellow), "HostPath set as default Storage Class")
 				case 2:
-					fmt.Print((colorBlue), "Input the path [default: /cnvrg-hostpath-storage]: ")
-					caseInput := formatInput()
-					storage.Hostpath.Path = caseInput
+					if path := promptString("Input the path [default: /cnvrg-hostpath-storage]: "); path != "" {
+						storage.Hostpath.Path = path
+					}
 					storage.Hostpath.Enabled = true
 				case 3:
-					var input string
-					var policy = []string{"Retain", "Delete", "Recycle"}
-					done := true
-					for done {
-						fmt.Print((colorBlue), "Set the Reclaim Policy (Retain, Delete or Recycle): ")
-						fmt.Scanln(&input)
-						for _, s := range policy {
-							if input == s {
-								storage.Hostpath.ReclaimPolicy = input
-								done = false
-							}
-						}
-					}
+					storage.Hostpath.ReclaimPolicy = promptEnum("Set the Reclaim Policy (Retain, Delete or Recycle): ", "Retain", "Delete", "Recycle")
 					storage.Hostpath.Enabled = true
 				case 4:
 					fmt.Print((colorBlue), "Set the Node Selector")
-					nodeselector := createSlice()
+					nodeselector := promptMap("Format [key: value]; 'return' when done: ")
 					storage.Hostpath.NodeSelector = nodeselector
 					storage.Hostpath.Enabled = true
 				}
@@ -1380,18 +1577,14 @@ func gatherStorage(storage *Storage) {
 				fmt.Println((colorBlue), "Press '3' to set NFS as default Storage Class")
 				fmt.Println((colorBlue), "Press '4' to modify Reclaim Policy [default: Retain]")
 				fmt.Println((colorBlue), "Press '5' to Save and Exit")
-				fmt.Print((colorWhite), "Please make your selection: ")
-				caseInput := formatInput()
-				intVar, _ := strconv.Atoi(caseInput)
+				intVar := promptMenu("NFS Menu", 5)
 				switch intVar {
 				case 1:
-					fmt.Print((colorWhite), "Input the NFS server IP address: ")
-					ip := formatInput()
+					ip := promptHost("Input the NFS server IP address: ")
 					storage.Nfs.Server = ip
 					storage.Nfs.Enabled = true
 				case 2:
-					fmt.Print((colorWhite), "Input the NFS export path: ")
-					path := formatInput()
+					path := promptString("Input the NFS export path: ")
 					storage.Nfs.Path = path
 					storage.Nfs.Enabled = true
 				case 3:
@@ -1399,19 +1592,7 @@ func gatherStorage(storage *Storage) {
 					storage.Nfs.DefaultSc = true
 					fmt.Println((colorYellow), "NFS set as default Storage Class")
 				case 4:
-					var input string
-					var policy = []string{"Retain", "Delete", "Recycle"}
-					done := true
-					for done {
-						fmt.Print((colorBlue), "Set the Reclaim Policy (Retain, Delete or Recycle): ")
-						fmt.Scanln(&input)
-						for _, s := range policy {
-							if input == s {
-								storage.Nfs.ReclaimPolicy = input
-								done = false
-							}
-						}
-					}
+					storage.Nfs.ReclaimPolicy = promptEnum("Set the Reclaim Policy (Retain, Delete or Recycle): ", "Retain", "Delete", "Recycle")
 					storage.Nfs.Enabled = true
 				}
 				if intVar == 5 {
@@ -1446,48 +1627,37 @@ func gatherSso(sso *Sso) {
 		fmt.Println((colorBlue), "Press '7' to modify Azure Tenant")
 		fmt.Println((colorBlue), "Press '8' to modify OIDC Issuer URL")
 		fmt.Println((colorBlue), "Press '9' to Save and Exit Single Sign On menu")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
+		intVar := promptMenu("Single Sign On Menu", 9)
 		switch intVar {
 		case 1:
 			sso.Enabled = true
 			fmt.Println((colorYellow), "Single Sign On Enabled")
 			InfoLogger.Printf("Single Sign on Enable set to %v", sso.Enabled)
 		case 2:
-			fmt.Print((colorWhite), "Input the Admin User: ")
-			admin := formatInput()
+			admin := promptString("Input the Admin User: ")
 			sso.AdminUser = admin
 			sso.Enabled = true
 		case 3:
-			fmt.Print((colorWhite), "Input the SSO Provider: ")
-			provider := formatInput()
+			provider := promptLower("Input the SSO Provider: ")
 			sso.Provider = provider
 			sso.Enabled = true
 		case 4:
-			fmt.Print((colorWhite), "Input the Email Domain: ")
-			domain := createSlice()
+			domain := promptList("Input the Email Domain", nil)
 			sso.EmailDomain = domain
 			sso.Enabled = true
 		case 5:
-			fmt.Print((colorWhite), "Input the Client ID: ")
-			clientid := formatInput()
+			clientid := promptString("Input the Client ID: ")
 			sso.ClientId = clientid
 			sso.Enabled = true
 		case 6:
-			fmt.Print((colorWhite), "Input the Client Secret: ")
-			var clientsecret string
-			fmt.Scan(&clientsecret)
-			sso.ClientSecret = clientsecret
+			sso.ClientSecret = readSecret("Input the Client Secret: ")
 			sso.Enabled = true
 		case 7:
-			fmt.Print((colorWhite), "Input the Azure Tenant: ")
-			azure := formatInput()
+			azure := promptString("Input the Azure Tenant: ")
 			sso.AzureTenant = azure
 			sso.Enabled = true
 		case 8:
-			fmt.Print((colorWhite), "Input the OIDC Issuer URL: ")
-			oidc := formatInput()
+			oidc := promptURL("Input the OIDC Issuer URL: ")
 			sso.OidcIssuerUrl = oidc
 			sso.Enabled = true
 		}
@@ -1500,58 +1670,75 @@ func gatherSso(sso *Sso) {
 
 // Function that will take a name and create a file
 // in the root directory from Template
-func createFile(name string, template *Template) {
+func createFile(name string, template *Template) error {
 
-	// Creating an empty file
-	// Using Create() function
-	myfile, e := os.Create(name)
-	if e != nil {
-		log.Fatal(e)
+	// Render the template first so a broken values file is never written
+	data, err := renderValues(template)
+	if err != nil {
+		ErrorLogger.Println(err)
+		return err
 	}
-	log.Println(myfile)
-
-	// Execute the template and write to the file which was previously created
-	f := temp.Execute(myfile, template)
-	if f != nil {
-		log.Print(f)
-	}
-	myfile.Close()
+	InfoLogger.Printf("Writing the values to %v\n", name)
+	return os.WriteFile(name, data, 0644)
 }
 
-func mainMenu() {
-	for {
-		fmt.Println()
-		fmt.Println((colorGreen), "------------------------------- Main Menu -------------------------------")
-		fmt.Println((colorGreen), "Please make a selection to modify the values file for the cnvrg.io install")
-		fmt.Println((colorBlue), "Press '1' to select Quick Start")
-		fmt.Println((colorBlue), "Press '2' to select Advanced Options")
-		fmt.Println((colorBlue), "Press '3' to Save and Generate the values.yaml file")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
-		switch intVar {
-		case 1:
-			quickStart()
-		case 2:
-			advancedOptions()
+func mainMenu() navigation {
+	fmt.Println()
+	fmt.Println((colorGreen), "------------------------------- Main Menu -------------------------------")
+	fmt.Println((colorGreen), "Please make a selection to modify the values file for the cnvrg.io install")
+	fmt.Println((colorBlue), "Press '1' to select Quick Start")
+	fmt.Println((colorBlue), "Press '2' to select Advanced Options")
+	fmt.Println((colorBlue), "Press '3' to Review and Generate the values.yaml file")
+	fmt.Println((colorBlue), "Press '4' to Undo the last change")
+	fmt.Println((colorBlue), "Press '5' to Reset a section to its defaults")
+	fmt.Println((colorBlue), "Press '6' to Exit without writing the values")
+	intVar := promptMenu("Main Menu", 6)
+	switch intVar {
+	case 1:
+		return navigation{next: quickStart}
+	case 2:
+		return navigation{next: advancedOptions}
+	case 3:
+		return navigation{next: reviewValues}
+	case 4:
+		undoChange()
+	case 5:
+		resetSection()
+	case 6:
+		if confirmExit() {
+			return navigation{done: true}
 		}
-		if intVar == 3 {
-			fmt.Println((colorWhite), "Exiting and generating the values.yaml file")
-			finaltemp := Template{clusterdomain, internalDomain, labels, annotations, network, logging, registry, tenancy,
-				sso, storage, configreloader, capsule, backup, gpu, monitoring, controlplane, dbs}
-			err := temp.Execute(os.Stdout, finaltemp)
-			if err != nil {
-				log.Print(err)
-			}
-			createFile("values.yaml", &finaltemp)
-			outputHelm()
-			os.Exit(0)
+	}
+	return navigation{}
+}
 
-		}
+// Validates the values, prints them with the secrets masked and writes the values and secrets files
+func saveValues(finaltemp Template) error {
+	if errs := validateTemplate(finaltemp); len(errs) > 0 {
+		printValidationErrors(errs)
+		return fmt.Errorf("Please fix the values above before generating the values file")
+	}
+	if err := checkSmtpServer(finaltemp.ControlPlane); err != nil {
+		return err
+	}
+	fmt.Printf("%v Exiting and generating the %v file\n", colorWhite, valuesFile)
+	masked := maskSecrets(finaltemp)
+	data, err := renderValues(&masked)
+	if err != nil {
+		return err
 	}
+	fmt.Print(string(data))
+	if err := createFile(valuesFile, &finaltemp); err != nil {
+		return err
+	}
+	if err := createSecretsFile(secretsFile, &finaltemp); err != nil {
+		return err
+	}
+	outputHelm()
+	return nil
 }
 
-func quickStart() {
+func quickStart() navigation {
 	InfoLogger.Println("In the quickStart function")
 
 	fmt.Println()
@@ -1559,137 +1746,164 @@ func quickStart() {
 	fmt.Println((colorGreen), "Begin Quick Start Guide or Exit to Main Menu")
 	fmt.Println((colorBlue), "Press '1' to begin Quick Start")
 	fmt.Println((colorBlue), "Press '2' To Exit and return to Main Menu")
-	fmt.Print((colorWhite), "Please make your selection: ")
-	for {
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
-		switch intVar {
-		case 1:
-			fmt.Println("Starting the Quick Start Guide")
-			gatherClusterDomain(&clusterdomain)
-			gatherAnnotations(&annotations)
-			mainMenu()
-		}
-		if intVar == 2 {
-			fmt.Println((colorWhite), "Exiting and returning to Main Menu")
-			mainMenu()
-		}
+	intVar := promptMenu("Quick Start Menu", 2)
+	switch intVar {
+	case 1:
+		fmt.Println("Starting the Quick Start Guide")
+		before := copyTemplate(currentTemplate())
+		gatherProfile()
+		gatherClusterDomain(&clusterdomain)
+		gatherAnnotations(&annotations)
+		recordChange("the Quick Start", before)
+	}
+	if intVar == 2 {
+		fmt.Println((colorWhite), "Exiting and returning to Main Menu")
 	}
+	return navigation{back: true}
 }
 
-func advancedOptions() {
+func advancedOptions() navigation {
 	InfoLogger.Println("In the advancedOptions function")
-	for {
-		fmt.Println()
-		fmt.Println((colorGreen), "---------------------------- Advanced Options Menu ----------------------------")
-		fmt.Println((colorGreen), "Please make a selection to modify the values file for the cnvrg.io install")
-		fmt.Println((colorBlue), "Press '1' To modify Labeling---------------->[ Labels, Annotations or Internal Domain ]")
-		fmt.Println((colorBlue), "Press '2' To modify Networks settings------->[ Istio, NodePort, HTTPS ]")
-		fmt.Println((colorBlue), "Press '3' To modify Logging settings-------->[ Kibana, ElasticAlert, Fluentbit ]")
-		fmt.Println((colorBlue), "Press '4' To modify Registry settings------->[ URL, Username, Password ]")
-		fmt.Println((colorBlue), "Press '5' To modify Tenancy settings-------->[ Node Selector ]")
-		fmt.Println((colorBlue), "Press '6' To modify Single Sign On settings->[ Admin, Provider, Azure Tenant ]")
-		fmt.Println((colorBlue), "Press '7' To modify Storage settings-------->[ NFS, Hostpath ] ")
-		fmt.Println((colorBlue), "Press '8' To modify Miscellaneous settings-->[ Backup, GPU, ConfigLoader, Capsule ]")
-		fmt.Println((colorBlue), "Press '9' To modify Monitoring settings----->[ Prometheus, Grafana, Exporters ]")
-		fmt.Println((colorBlue), "Press '10' To modify Control Plane settings->[ CP Image, CP Services, SMTP ]")
-		fmt.Println((colorBlue), "Press '11' To modify Database settings------>[ Minio, Postgres, Redis ]")
-		fmt.Println((colorBlue), "Press '12' To Exit and return to Main Menu")
-		fmt.Print((colorWhite), "Please make your selection: ")
-		caseInput := formatInput()
-		intVar, _ := strconv.Atoi(caseInput)
-		switch intVar {
-		case 1:
-			for {
-				fmt.Println()
-				fmt.Println((colorGreen), "----Labels, Annotations Internal Domain Menu----")
-				fmt.Println((colorGreen), "Update Labels, Annotations or Internal Domain values")
-				fmt.Println((colorBlue), "Press '1' To modify Labels")
-				fmt.Println((colorBlue), "Press '2' To modify Annotations")
-				fmt.Println((colorBlue), "Press '3' To modify Internal Domain")
-				fmt.Println((colorBlue), "Press '4' To Save and Exit")
-				fmt.Print((colorWhite), "Please make your selection: ")
-				caseInput := formatInput()
-				intVar, _ := strconv.Atoi(caseInput)
-				switch intVar {
-				case 1:
-					gatherLabels(&labels)
-				case 2:
-					gatherAnnotations(&annotations)
-				case 3:
-					gatherInternalDomain(&internalDomain)
-				}
-				if intVar == 4 {
-					fmt.Println((colorYellow), "Saving and Exiting menu")
-					break
-				}
-			}
-		case 2:
-			gatherNetworking(&network)
-		case 3:
-			gatherLogging(&logging)
-		case 4:
-			gatherRegistry(&registry)
-		case 5:
-			gatherTenancy(&tenancy)
-		case 6:
-			gatherSso(&sso)
-		case 7:
-			gatherStorage(&storage)
-		case 8:
-			for {
-				fmt.Println()
-				fmt.Println((colorGreen), "----Backup, GPU, Capsule and GPU Menu----")
-				fmt.Println((colorGreen), "Update Backup, GPU, Capsule and GPU values")
-				fmt.Println((colorBlue), "Press '1' To modify Backup settings")
-				fmt.Println((colorBlue), "Press '2' To modify Capsule settings")
-				fmt.Println((colorBlue), "Press '3' To disable NvidiaDp or HabanaDp GPU")
-				fmt.Println((colorBlue), "Press '4' To disable ConfigReloader")
-				fmt.Println((colorBlue), "Press '5' To Exit modifying settings")
-				fmt.Print((colorBlue), "Please make your selection: ")
-				caseInput := formatInput()
-				intVar, _ := strconv.Atoi(caseInput)
-				switch intVar {
-				case 1:
-					gatherBackup(&backup)
-				case 2:
-					gatherCapsule(&capsule)
-				case 3:
-					gatherGpu(&gpu)
-				case 4:
-					gatherConfigReloader(&configreloader)
-				}
-				if intVar == 5 {
-					fmt.Println((colorYellow), "Saving changes and exiting")
-					break
-				}
-			}
-		case 9:
-			gatherMonitoring(&monitoring)
-		case 10:
-			gatherControlPlane(&controlplane)
-		case 11:
-			gatherDbs(&dbs)
-		}
-		if intVar == 12 {
-			fmt.Println((colorWhite), "Exiting and returning to Main Menu")
-			mainMenu()
-		}
+	fmt.Println()
+	fmt.Println((colorGreen), "---------------------------- Advanced Options Menu ----------------------------")
+	fmt.Println((colorGreen), "Please make a selection to modify the values file for the cnvrg.io install")
+	fmt.Println((colorBlue), "Press '1' To modify Labeling---------------->[ Labels, Annotations or Internal Domain ]")
+	fmt.Println((colorBlue), "Press '2' To modify Networks settings------->[ Istio, NodePort, HTTPS ]")
+	fmt.Println((colorBlue), "Press '3' To modify Logging settings-------->[ Kibana, ElasticAlert, Fluentbit ]")
+	fmt.Println((colorBlue), "Press '4' To modify Registry settings------->[ URL, Username, Password ]")
+	fmt.Println((colorBlue), "Press '5' To modify Tenancy settings-------->[ Node Selector ]")
+	fmt.Println((colorBlue), "Press '6' To modify Single Sign On settings->[ Admin, Provider, Azure Tenant ]")
+	fmt.Println((colorBlue), "Press '7' To modify Storage settings-------->[ NFS, Hostpath ] ")
+	fmt.Println((colorBlue), "Press '8' To modify Miscellaneous settings-->[ Backup, GPU, ConfigLoader, Capsule ]")
+	fmt.Println((colorBlue), "Press '9' To modify Monitoring settings----->[ Prometheus, Grafana, Exporters ]")
+	fmt.Println((colorBlue), "Press '10' To modify Control Plane settings->[ CP Image, CP Services, SMTP ]")
+	fmt.Println((colorBlue), "Press '11' To modify Database settings------>[ Minio, Postgres, Redis ]")
+	fmt.Println((colorBlue), "Press '12' To Undo the last change")
+	fmt.Println((colorBlue), "Press '13' To Reset a section to its defaults")
+	fmt.Println((colorBlue), "Press '14' To Exit and return to Main Menu")
+	intVar := promptMenu("Advanced Options Menu", 14)
+	switch {
+	case intVar <= 11:
+		// The first section is the Cluster Domain, which is set in the Quick Start
+		editSection(menuSections[intVar])
+	case intVar == 12:
+		undoChange()
+	case intVar == 13:
+		resetSection()
+	case intVar == 14:
+		fmt.Println((colorWhite), "Exiting and returning to Main Menu")
+		return navigation{back: true}
 	}
-
+	return navigation{}
 }
 
 // valuesCmd represents the values command
 var valuesCmd = &cobra.Command{
 	Use:   "values",
 	Short: "Command to generate a values file through user input",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Long: `Generate a values file for the cnvrg.io Helm chart.
+
+With no flags the values are gathered through the menu driven wizard.
+Passing an answers file or any of the value flags generates the values
+file without prompts, for example:
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	Run: func(cmd *cobra.Command, args []string) {
+  cnvrg-deploy-cli create values --answers answers.yaml
+  cnvrg-deploy-cli create values --cluster-domain aws.dilerous.cloud --registry-url docker.io
+
+An existing values file can be loaded back into the menus to be edited:
+
+  cnvrg-deploy-cli create values --from values.yaml
+
+The answers of the menus can be recorded and replayed to generate the
+same values file again:
+
+  cnvrg-deploy-cli create values --record session.yaml
+  cnvrg-deploy-cli create values --replay session.yaml
+
+The values can also be edited in a full screen UI instead of the menus:
+
+  cnvrg-deploy-cli create values --tui`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		if err := validateSecretsMode(secretsMode); err != nil {
+			return err
+		}
+		if tuiMode && (recordFile != "" || replayFile != "") {
+			return fmt.Errorf("--tui can not be used with --record or --replay")
+		}
+		if templateFile != "" {
+			t, err := parseValuesTemplate(templateFile)
+			if err != nil {
+				ErrorLogger.Println(err)
+				return err
+			}
+			temp = t
+		}
+
+		// Start from an existing values file when one is provided
+		finaltemp := currentTemplate()
+		if fromFile != "" {
+			t, err := readValuesFile(fromFile)
+			if err != nil {
+				ErrorLogger.Println(err)
+				return err
+			}
+			finaltemp = t
+		}
+		if profileName != "" {
+			if err := applyProfile(profileName, &finaltemp); err != nil {
+				ErrorLogger.Println(err)
+				return err
+			}
+		}
+		if answersFile != "" {
+			if err := loadAnswers(answersFile, &finaltemp); err != nil {
+				ErrorLogger.Println(err)
+				return err
+			}
+		}
+		// The release is set before the flags so a single image can still be overridden
+		if releaseName != "" {
+			if cmd.Flags().Changed("image-hub") {
+				finaltemp.ClusterDomain.ImageHub = flagTemplate.ClusterDomain.ImageHub
+			}
+			if err := applyRelease(releaseName, &finaltemp); err != nil {
+				ErrorLogger.Println(err)
+				return err
+			}
+		}
+		applyFieldFlags(cmd, &finaltemp)
+
+		// Generate the values file without prompts when answers are provided
+		interactive := fromFile != "" || recordFile != "" || replayFile != "" || tuiMode
+		if !interactive && (answersFile != "" || fieldFlagsChanged(cmd)) {
+			if profileName != "" {
+				if err := checkProfile(profileName, finaltemp); err != nil {
+					ErrorLogger.Println(err)
+					return err
+				}
+			}
+			return generateValues(finaltemp)
+		}
+		if tuiMode {
+			t, saved, err := runTui(finaltemp)
+			if err != nil {
+				ErrorLogger.Println(err)
+				return err
+			}
+			if !saved {
+				fmt.Println((colorYellow), "Exited without saving the values")
+				return nil
+			}
+			applyTemplate(t)
+			return saveValues(t)
+		}
+		applyTemplate(finaltemp)
+		if err := startSession(); err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
 
 		//Start of program to ask user for Input
 		InfoLogger.Println((colorWhite), "You are in the values main function")
@@ -1699,6 +1913,65 @@ to quickly create a Cobra application.`,
 		fmt.Println((colorGreen), "Here is the Helm Chart docs for cnvrg.io")
 		fmt.Println((colorBlue), "https://github.com/AccessibleAI/cnvrg-operator")
 
-		mainMenu()
+		if err := runMenus(mainMenu); err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
+		return nil
 	},
 }
+
+// Menu for the labels, annotations and internal domain
+func gatherLabeling() {
+	for {
+		fmt.Println()
+		fmt.Println((colorGreen), "----Labels, Annotations Internal Domain Menu----")
+		fmt.Println((colorGreen), "Update Labels, Annotations or Internal Domain values")
+		fmt.Println((colorBlue), "Press '1' To modify Labels")
+		fmt.Println((colorBlue), "Press '2' To modify Annotations")
+		fmt.Println((colorBlue), "Press '3' To modify Internal Domain")
+		fmt.Println((colorBlue), "Press '4' To Save and Exit")
+		intVar := promptMenu("Labels, Annotations Internal Domain Menu", 4)
+		switch intVar {
+		case 1:
+			gatherLabels(&labels)
+		case 2:
+			gatherAnnotations(&annotations)
+		case 3:
+			gatherInternalDomain(&internalDomain)
+		}
+		if intVar == 4 {
+			fmt.Println((colorYellow), "Saving and Exiting menu")
+			break
+		}
+	}
+}
+
+// Menu for the backup, capsule, GPU and config reloader settings
+func gatherMisc() {
+	for {
+		fmt.Println()
+		fmt.Println((colorGreen), "----Backup, GPU, Capsule and GPU Menu----")
+		fmt.Println((colorGreen), "Update Backup, GPU, Capsule and GPU values")
+		fmt.Println((colorBlue), "Press '1' To modify Backup settings")
+		fmt.Println((colorBlue), "Press '2' To modify Capsule settings")
+		fmt.Println((colorBlue), "Press '3' To disable NvidiaDp or HabanaDp GPU")
+		fmt.Println((colorBlue), "Press '4' To disable ConfigReloader")
+		fmt.Println((colorBlue), "Press '5' To Exit modifying settings")
+		intVar := promptMenu("Backup, GPU, Capsule and GPU Menu", 5)
+		switch intVar {
+		case 1:
+			gatherBackup(&backup)
+		case 2:
+			gatherCapsule(&capsule)
+		case 3:
+			gatherGpu(&gpu)
+		case 4:
+			gatherConfigReloader(&configreloader)
+		}
+		if intVar == 5 {
+			fmt.Println((colorYellow), "Saving changes and exiting")
+			break
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+// Answers the prompts with the lines of input
+func setInput(t *testing.T, lines ...string) {
+	old := stdin
+	t.Cleanup(func() { stdin = old })
+	stdin = bufio.NewReader(strings.NewReader(strings.Join(lines, "\n") + "\n"))
+}
+
+// Every ingress type of the Ingress Menu sets the type and the Istio toggles
+func TestGatherNetworkingIngressType(t *testing.T) {
+	type want struct {
+		istio     bool
+		istioGw   bool
+		typeValue string
+	}
+	// The ingress type is set from the Ingress Menu of a Networking which uses nodeport
+	for ingressType, w := range map[string]want{
+		"istio":     {istio: true, istioGw: true, typeValue: "istio"},
+		"ingress":   {istio: false, istioGw: false, typeValue: "ingress"},
+		"nodeport":  {istio: false, istioGw: false, typeValue: "nodeport"},
+		"openshift": {istio: false, istioGw: false, typeValue: "openshift"},
+	} {
+		input := []string{"2", "1", ingressType}
+		if ingressType == "istio" {
+			// Leave the Istio Menu
+			input = append(input, "5")
+		}
+		setInput(t, append(input, "2", "5")...)
+		network := Networking{Ingress: Ingress{Type: "nodeport"}}
+
+		gatherNetworking(&network)
+
+		if network.Ingress.Type != w.typeValue || network.Istio.Enabled != w.istio || network.Ingress.IstioGwEnabled != w.istioGw {
+			t.Errorf("%v: the ingress type is %q, istio: %v, istio gateway: %v, want %q, %v, %v", ingressType,
+				network.Ingress.Type, network.Istio.Enabled, network.Ingress.IstioGwEnabled, w.typeValue, w.istio, w.istioGw)
+		}
+	}
+}
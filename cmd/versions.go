@@ -0,0 +1,236 @@
+/*
+Copyright © 2022 BRAD SOPER	BRADLEY.SOPER@CNVRG.IO
+*/
+
+package cmd
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// The catalog shipped with the cli
+//
+//go:embed versions.yaml
+var embeddedCatalog []byte
+
+// Set by the flags of the versions command and the values command
+var (
+	catalogSource  string
+	versionsFormat string
+	releaseName    string
+)
+
+// The images and chart version of every cnvrg.io release
+type releaseCatalog struct {
+	Releases []catalogRelease `yaml:"releases" json:"releases"`
+}
+
+// Used in the releaseCatalog struct, the images are relative to the image hub like app:v4.7.33
+type catalogRelease struct {
+	Release           string `yaml:"release" json:"release"`
+	Chart             string `yaml:"chart" json:"chart"`
+	ControlPlaneImage string `yaml:"controlPlaneImage" json:"controlPlaneImage"`
+	AgentTag          string `yaml:"agentTag" json:"agentTag"`
+	MpiImage          string `yaml:"mpiImage" json:"mpiImage"`
+	MpiKubectlImage   string `yaml:"mpiKubectlImage" json:"mpiKubectlImage"`
+	// The default images of the other components, by the component names of 'images list'
+	Images map[string]string `yaml:"images" json:"images,omitempty"`
+}
+
+// Returns the image of the component in the release, empty when the release has none
+func (r catalogRelease) image(component string) string {
+	switch component {
+	case "controlPlane":
+		return r.ControlPlaneImage
+	case "mpi":
+		return r.MpiImage
+	case "mpiKubectlDelivery":
+		return r.MpiKubectlImage
+	}
+	return r.Images[component]
+}
+
+// versionsCmd represents the versions command
+var versionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "List the cnvrg.io releases and the image tags which belong together",
+	Long: `List every cnvrg.io release in the catalog with its chart version,
+control plane image, agent tag and MPI images. Use a release with
+'create values --release' to set all of them at once, for example:
+
+  cnvrg-deploy-cli versions
+  cnvrg-deploy-cli create values --release 4.7.33
+
+The catalog is embedded in the cli, use --catalog to read a newer
+catalog from a file or a URL.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		InfoLogger.Println("In the versions command")
+		catalog, err := loadCatalog(catalogSource)
+		if err != nil {
+			ErrorLogger.Println(err)
+			return err
+		}
+		return printCatalog(catalog, versionsFormat)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionsCmd)
+
+	versionsCmd.Flags().StringVar(&catalogSource, "catalog", "", "file or URL of the catalog, the embedded catalog when empty")
+	versionsCmd.Flags().StringVar(&versionsFormat, "format", "text", "output format [text|json]")
+}
+
+// The catalogs already read by their source, a catalog URL is only fetched once
+var loadedCatalogs = map[string]releaseCatalog{}
+
+// Reads the catalog from a file or a URL, or the embedded catalog when the source is empty
+func loadCatalog(source string) (releaseCatalog, error) {
+	if catalog, ok := loadedCatalogs[source]; ok {
+		return catalog, nil
+	}
+	var catalog releaseCatalog
+	data := embeddedCatalog
+	if source != "" {
+		var err error
+		data, err = readCatalogSource(source)
+		if err != nil {
+			return catalog, err
+		}
+	}
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return catalog, fmt.Errorf("unable to parse the catalog: %w", err)
+	}
+	if len(catalog.Releases) == 0 {
+		return catalog, fmt.Errorf("no releases found in the catalog")
+	}
+	for _, r := range catalog.Releases {
+		images := []string{r.ControlPlaneImage, r.MpiImage, r.MpiKubectlImage}
+		for _, image := range r.Images {
+			images = append(images, image)
+		}
+		for _, image := range images {
+			if strings.Contains(image, "/") {
+				return catalog, fmt.Errorf("the image %v of release %v must be relative to the image hub, like %v", image, r.Release, imageName(image))
+			}
+		}
+	}
+	loadedCatalogs[source] = catalog
+	return catalog, nil
+}
+
+// Returns the contents of a catalog file or URL
+func readCatalogSource(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		InfoLogger.Printf("Reading the catalog %v\n", source)
+		return os.ReadFile(source)
+	}
+	InfoLogger.Printf("Fetching the catalog %v\n", source)
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch the catalog: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch the catalog: %v", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Returns the release from the catalog, a leading v is ignored
+func (c releaseCatalog) find(name string) (catalogRelease, error) {
+	name = strings.TrimPrefix(name, "v")
+	var names []string
+	for _, r := range c.Releases {
+		if r.Release == name {
+			return r, nil
+		}
+		names = append(names, r.Release)
+	}
+	return catalogRelease{}, fmt.Errorf("unknown release %v, the releases in the catalog are: %v", name, strings.Join(names, ", "))
+}
+
+// Returns the release the control plane image belongs to, if any
+func (c releaseCatalog) findByImage(image string) (catalogRelease, bool) {
+	for _, r := range c.Releases {
+		if image != "" && imageName(image) == imageName(r.ControlPlaneImage) {
+			return r, true
+		}
+	}
+	return catalogRelease{}, false
+}
+
+// Returns the name and tag of the image without its registry and repository path
+func imageName(image string) string {
+	return image[strings.LastIndex(image, "/")+1:]
+}
+
+// Sets the images of the release on the Template as full references on the image hub
+func applyRelease(name string, t *Template) error {
+	catalog, err := loadCatalog(catalogSource)
+	if err != nil {
+		return err
+	}
+	r, err := catalog.find(name)
+	if err != nil {
+		return err
+	}
+	InfoLogger.Printf("Using the images of release %v\n", r.Release)
+	hub := t.ClusterDomain.ImageHub
+	t.ControlPlane.Image = hubImage(hub, r.ControlPlaneImage)
+	t.ControlPlane.BaseConfigAgentTag = r.AgentTag
+	t.ControlPlane.MpiImage = hubImage(hub, r.MpiImage)
+	t.ControlPlane.MpiKubectlImage = hubImage(hub, r.MpiKubectlImage)
+	fmt.Printf("%v Using the images of release %v, install it with chart version %v\n", colorGreen, r.Release, r.Chart)
+	return nil
+}
+
+// Adds an error when the agent tag does not belong to the release of the control plane image
+func validateRelease(cp ControlPlane, add func(string, string, ...interface{})) {
+	catalog, err := loadCatalog(catalogSource)
+	if err != nil {
+		return
+	}
+	r, ok := catalog.findByImage(cp.Image)
+	if !ok {
+		return
+	}
+	if cp.BaseConfigAgentTag != "" && cp.BaseConfigAgentTag != r.AgentTag {
+		add("controlPlane.baseConfig.agentCustomTag", "must be %v for release %v, got %q", r.AgentTag, r.Release, cp.BaseConfigAgentTag)
+	}
+}
+
+// Prints the catalog in the format requested
+func printCatalog(catalog releaseCatalog, format string) error {
+	switch format {
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RELEASE\tCHART\tCONTROL PLANE\tAGENT TAG\tMPI\tKUBECTL DELIVERY")
+		for _, r := range catalog.Releases {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", r.Release, r.Chart, r.ControlPlaneImage, r.AgentTag, r.MpiImage, r.MpiKubectlImage)
+		}
+		return w.Flush()
+	case "json":
+		out, err := json.MarshalIndent(catalog.Releases, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	default:
+		return fmt.Errorf("unknown format %v, must be one of text or json", format)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The release writes the same references as the images listed for the values
+func TestApplyReleaseImages(t *testing.T) {
+	for _, hub := range []string{"", "registry.example.com/cnvrg/"} {
+		tmpl := defaultTemplate()
+		tmpl.ClusterDomain.ImageHub = hub
+		if err := applyRelease("4.7.33", &tmpl); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{
+			"controlPlane":       hubImage(hub, "app:v4.7.33"),
+			"mpi":                hubImage(hub, "mpi-operator:v0.2.3"),
+			"mpiKubectlDelivery": hubImage(hub, "kubectl-delivery:v0.2.3"),
+		}
+		images, err := requiredImages(tmpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, image := range images {
+			if w, ok := want[image.Name]; ok && image.Image != w {
+				t.Errorf("the %v image is %v with the hub %q, want %v", image.Name, image.Image, hub, w)
+			}
+		}
+		if tmpl.ControlPlane.Image != want["controlPlane"] {
+			t.Errorf("the control plane image is %v with the hub %q, want %v", tmpl.ControlPlane.Image, hub, want["controlPlane"])
+		}
+	}
+}
+
+// The agent tag is checked against the catalog given with --catalog
+func TestValidateReleaseWithCatalog(t *testing.T) {
+	catalog := filepath.Join(t.TempDir(), "catalog.yaml")
+	data := "releases:\n- release: 9.9.9\n  chart: 9.9.9\n  controlPlaneImage: app:v9.9.9\n  agentTag: v9.9.9\n"
+	if err := os.WriteFile(catalog, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(source string) { catalogSource = source }(catalogSource)
+	catalogSource = catalog
+
+	tmpl := defaultTemplate()
+	tmpl.ClusterDomain.ClusterDomain = "example.com"
+	tmpl.ControlPlane.Image = "docker.io/cnvrg/app:v9.9.9"
+	tmpl.ControlPlane.BaseConfigAgentTag = "v4.7.33"
+	found := false
+	for _, err := range validateTemplate(tmpl) {
+		if err.Field == "controlPlane.baseConfig.agentCustomTag" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("the agent tag was not checked against the release of the catalog")
+	}
+}
+
+// Images with a registry or repository path are refused in a catalog
+func TestLoadCatalogRelativeImages(t *testing.T) {
+	catalog := filepath.Join(t.TempDir(), "catalog.yaml")
+	data := "releases:\n- release: 9.9.9\n  controlPlaneImage: cnvrg/app:v9.9.9\n"
+	if err := os.WriteFile(catalog, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadCatalog(catalog); err == nil {
+		t.Error("the catalog with an image path was loaded")
+	}
+}
@@ -2,9 +2,20 @@ module cnvrg-deploy-cli
 
 go 1.18
 
-require github.com/spf13/cobra v1.5.0
+require (
+	github.com/gdamore/tcell/v2 v2.8.1
+	github.com/spf13/cobra v1.5.0
+	golang.org/x/term v0.28.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
+	github.com/gdamore/encoding v1.0.1 // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
+	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
+	github.com/mattn/go-runewidth v0.0.16 // indirect
+	github.com/rivo/uniseg v0.4.3 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
 )